@@ -3,9 +3,11 @@ package assert
 
 import (
 	"testing"
+
+	"github.com/AntonyChR/go-utils/bytes"
 )
 
-func assert(value bool, args []any){
+func assert(value bool, args []any, details ...string){
     if !value {
         msg := ""
         var t *testing.T
@@ -28,6 +30,10 @@ func assert(value bool, args []any){
             }
         }
 
+        for _, d := range details {
+            msg += "\n" + d
+        }
+
         if t != nil {
             t.Error(msg)
         } else {
@@ -125,6 +131,37 @@ func AssertNe[K comparable](left_value, right_value K, args ...any) {
     assert(left_value != right_value, args)
 }
 
+// AssertBytesEq checks if two byte slices are equal. If they differ, the
+// error message is followed by a hex dump of the differing rows with the
+// mismatched bytes marked, as produced by bytes.Diff. The slices are
+// aligned, so an inserted or missing byte shows up as "--" on the other side
+// instead of marking the rest of the slice.
+//
+// Parameters:
+//  - left_value: The first slice to compare.
+//  - right_value: The second slice to compare.
+//  - args: Optional arguments. Can include:
+//      - msg (string): A custom error message.
+//      - t (*testing.T): A testing.T instance to log errors.
+//
+// Usage:
+//  AssertBytesEq(got, want, "unexpected frame", t)
+//
+// Example output:
+//  unexpected frame
+//  1 byte(s) differ (1 changed, 0 deleted, 0 inserted), first at offset 0x1 (len 3 vs 3)
+//  00000000 a: 01 02 03                                          |...|
+//  00000000 b: 01 ff 03                                          |...|
+//                 ^^
+//
+// Notes:
+//  - nil and empty slices are considered equal.
+//  - The same panic and default message rules as Assert apply.
+func AssertBytesEq(left_value, right_value []byte, args ...any) {
+    diff := bytes.Diff(left_value, right_value, &bytes.DumpOptions{Plain: true})
+    assert(diff == "", args, diff)
+}
+
 // Must is a generic function that simplifies error handling by enforcing an automatic panic
 // when an error is encountered. It accepts two parameters: a value `x` of any type `T` 
 // and an `error` value `err`.
@@ -1,6 +1,9 @@
 package assert
 
-import "testing"
+import (
+  "strings"
+  "testing"
+)
 
 func TestAssert(t *testing.T) {
   Assert(true,"the tested value is false", t)
@@ -13,3 +16,16 @@ func TestAssertEq(t *testing.T) {
 func TestAssertNe(t *testing.T) {
   AssertNe(true,false,"the tested values are equal", t)
 }
+
+func TestAssertBytesEq(t *testing.T) {
+  AssertBytesEq([]byte{1, 2, 3}, []byte{1, 2, 3}, "the tested slices are not equal", t)
+  AssertBytesEq(nil, []byte{}, "nil and empty slices must be equal", t)
+
+  defer func() {
+    msg, _ := recover().(string)
+    if !strings.Contains(msg, "frame mismatch\n1 byte(s) differ") || !strings.Contains(msg, "01 ff 03") {
+      t.Errorf("expected a visual diff in the message, got: %q", msg)
+    }
+  }()
+  AssertBytesEq([]byte{1, 2, 3}, []byte{1, 0xff, 3}, "frame mismatch")
+}
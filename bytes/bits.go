@@ -0,0 +1,212 @@
+package bytes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BitOrder selects how bit fields are laid out inside each byte.
+type BitOrder int
+
+const (
+	// MSBFirst fills each byte starting at its most significant bit and
+	// writes the most significant bit of every field first. This is the
+	// layout used by most network and sensor protocols.
+	MSBFirst BitOrder = iota
+	// LSBFirst fills each byte starting at its least significant bit and
+	// writes the least significant bit of every field first, as DEFLATE does.
+	LSBFirst
+)
+
+var (
+	// ErrBitUnderflow is returned when a read needs more bits than remain.
+	ErrBitUnderflow = errors.New("bytes: not enough bits left")
+	// ErrBitWidth is returned for field widths outside the range 0..64.
+	ErrBitWidth = errors.New("bytes: bit width must be between 0 and 64")
+)
+
+func lowMask(n int) uint64 {
+	if n >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<uint(n) - 1
+}
+
+// BitWriter packs fields of arbitrary bit width into a byte slice.
+// The zero value is a ready to use MSB-first writer.
+type BitWriter struct {
+	buf   []byte
+	nbits int
+	order BitOrder
+}
+
+// NewBitWriter creates an empty BitWriter using the given bit order.
+func NewBitWriter(order BitOrder) *BitWriter {
+	return &BitWriter{order: order}
+}
+
+// WriteBits appends the n low bits of v.
+//
+// Parameters:
+//   - v: The value to write. Bits above n are ignored.
+//   - n: The field width in bits, between 0 and 64.
+//
+// Returns:
+//   - ErrBitWidth if n is out of range.
+func (w *BitWriter) WriteBits(v uint64, n int) error {
+	if n < 0 || n > 64 {
+		return fmt.Errorf("%w: got %d", ErrBitWidth, n)
+	}
+	v &= lowMask(n)
+
+	for n > 0 {
+		off := w.nbits % 8
+		if off == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		take := min(8-off, n)
+		last := len(w.buf) - 1
+
+		if w.order == LSBFirst {
+			w.buf[last] |= byte(v&lowMask(take)) << uint(off)
+			v >>= uint(take)
+		} else {
+			chunk := (v >> uint(n-take)) & lowMask(take)
+			w.buf[last] |= byte(chunk) << uint(8-off-take)
+		}
+		n -= take
+		w.nbits += take
+	}
+	return nil
+}
+
+// WriteBool appends a single bit, 1 for true and 0 for false.
+func (w *BitWriter) WriteBool(b bool) {
+	var v uint64
+	if b {
+		v = 1
+	}
+	w.WriteBits(v, 1)
+}
+
+// Align pads the stream with zero bits up to the next byte boundary.
+func (w *BitWriter) Align() {
+	if off := w.nbits % 8; off != 0 {
+		w.nbits += 8 - off
+	}
+}
+
+// Len returns the number of bits written so far, including padding.
+func (w *BitWriter) Len() int {
+	return w.nbits
+}
+
+// Bytes returns the packed data. A trailing partial byte is zero padded.
+// The returned slice aliases the writer's buffer until the next write.
+func (w *BitWriter) Bytes() []byte {
+	return w.buf
+}
+
+// Reset discards all written data, keeping the bit order.
+func (w *BitWriter) Reset() {
+	w.buf = w.buf[:0]
+	w.nbits = 0
+}
+
+// BitReader extracts fields of arbitrary bit width from a byte slice.
+type BitReader struct {
+	buf   []byte
+	pos   int
+	order BitOrder
+}
+
+// NewBitReader creates a BitReader over buf using the given bit order.
+// The slice is not copied.
+func NewBitReader(buf []byte, order BitOrder) *BitReader {
+	return &BitReader{buf: buf, order: order}
+}
+
+// Remaining returns the number of unread bits.
+func (r *BitReader) Remaining() int {
+	return len(r.buf)*8 - r.pos
+}
+
+// Offset returns the current position in bits from the start of the buffer.
+func (r *BitReader) Offset() int {
+	return r.pos
+}
+
+// PeekBits returns the next n bits without consuming them.
+//
+// Parameters:
+//   - n: The field width in bits, between 0 and 64.
+//
+// Returns:
+//   - The field value, right aligned.
+//   - ErrBitWidth if n is out of range or ErrBitUnderflow if fewer than n
+//     bits remain.
+func (r *BitReader) PeekBits(n int) (uint64, error) {
+	if n < 0 || n > 64 {
+		return 0, fmt.Errorf("%w: got %d", ErrBitWidth, n)
+	}
+	if n > r.Remaining() {
+		return 0, fmt.Errorf("%w: need %d, have %d", ErrBitUnderflow, n, r.Remaining())
+	}
+
+	var v uint64
+	pos, shift := r.pos, 0
+	for n > 0 {
+		off := pos % 8
+		take := min(8-off, n)
+		b := uint64(r.buf[pos/8])
+
+		if r.order == LSBFirst {
+			v |= ((b >> uint(off)) & lowMask(take)) << uint(shift)
+			shift += take
+		} else {
+			v = v<<uint(take) | (b>>uint(8-off-take))&lowMask(take)
+		}
+		n -= take
+		pos += take
+	}
+	return v, nil
+}
+
+// ReadBits consumes and returns the next n bits.
+// It follows the same conventions as PeekBits.
+func (r *BitReader) ReadBits(n int) (uint64, error) {
+	v, err := r.PeekBits(n)
+	if err != nil {
+		return 0, err
+	}
+	r.pos += n
+	return v, nil
+}
+
+// ReadBool consumes a single bit and reports whether it is set.
+func (r *BitReader) ReadBool() (bool, error) {
+	v, err := r.ReadBits(1)
+	return v == 1, err
+}
+
+// SkipBits advances the reader by n bits.
+//
+// Returns:
+//   - ErrBitUnderflow if fewer than n bits remain; the position is unchanged.
+func (r *BitReader) SkipBits(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: got %d", ErrBitWidth, n)
+	}
+	if n > r.Remaining() {
+		return fmt.Errorf("%w: need %d, have %d", ErrBitUnderflow, n, r.Remaining())
+	}
+	r.pos += n
+	return nil
+}
+
+// Align skips any remaining bits of the current byte.
+func (r *BitReader) Align() {
+	if off := r.pos % 8; off != 0 {
+		r.pos += 8 - off
+	}
+}
@@ -0,0 +1,151 @@
+package bytes
+
+import (
+	stdbytes "bytes"
+	"errors"
+	"testing"
+)
+
+func TestBitWriterLayout(t *testing.T) {
+	tests := []struct {
+		order    BitOrder
+		expected []byte
+	}{
+		// 101 | 00001 | 1111 0000 -> 1010 0001 | 1111 0000
+		{MSBFirst, []byte{0xa1, 0xf0}},
+		// fields fill from bit 0: 101, then 00001 at bit 3, then 0xf0 in the next byte
+		{LSBFirst, []byte{0x0d, 0xf0}},
+	}
+
+	for _, tt := range tests {
+		w := NewBitWriter(tt.order)
+		w.WriteBits(0b101, 3)
+		w.WriteBits(0b00001, 5)
+		w.WriteBits(0xf0, 8)
+
+		if !stdbytes.Equal(w.Bytes(), tt.expected) {
+			t.Errorf("order %d: incorrect layout, expected = %08b, got = %08b", tt.order, tt.expected, w.Bytes())
+		}
+	}
+}
+
+func TestBitWriterAlign(t *testing.T) {
+	w := NewBitWriter(MSBFirst)
+	w.WriteBool(true)
+	w.Align()
+	w.WriteBits(0xff, 8)
+
+	if w.Len() != 16 {
+		t.Errorf("incorrect bit length, expected = 16, got = %d", w.Len())
+	}
+	if !stdbytes.Equal(w.Bytes(), []byte{0x80, 0xff}) {
+		t.Errorf("incorrect padding, got = %08b", w.Bytes())
+	}
+
+	if err := w.WriteBits(0, 65); !errors.Is(err, ErrBitWidth) {
+		t.Errorf("expected ErrBitWidth, got %v", err)
+	}
+}
+
+func TestBitReaderPeekSkip(t *testing.T) {
+	r := NewBitReader([]byte{0xa1, 0xf0}, MSBFirst)
+
+	v, err := r.PeekBits(3)
+	if err != nil || v != 0b101 {
+		t.Fatalf("incorrect peek, expected = 5, got = %d (%v)", v, err)
+	}
+	if r.Offset() != 0 {
+		t.Errorf("peek must not advance, offset = %d", r.Offset())
+	}
+
+	if err := r.SkipBits(3); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := r.ReadBits(5); v != 1 {
+		t.Errorf("incorrect field, expected = 1, got = %d", v)
+	}
+
+	r.Align()
+	if v, _ := r.ReadBits(4); v != 0xf {
+		t.Errorf("incorrect field after align, expected = 15, got = %d", v)
+	}
+	if r.Remaining() != 4 {
+		t.Errorf("incorrect remaining bits, expected = 4, got = %d", r.Remaining())
+	}
+}
+
+func TestBitReaderUnderflow(t *testing.T) {
+	r := NewBitReader([]byte{0xff}, LSBFirst)
+
+	if _, err := r.ReadBits(9); !errors.Is(err, ErrBitUnderflow) {
+		t.Errorf("expected ErrBitUnderflow, got %v", err)
+	}
+	if r.Offset() != 0 {
+		t.Errorf("failed read must not advance, offset = %d", r.Offset())
+	}
+	if err := r.SkipBits(9); !errors.Is(err, ErrBitUnderflow) {
+		t.Errorf("expected ErrBitUnderflow, got %v", err)
+	}
+	if _, err := r.ReadBits(8); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := r.ReadBool(); !errors.Is(err, ErrBitUnderflow) {
+		t.Errorf("expected ErrBitUnderflow, got %v", err)
+	}
+}
+
+func FuzzBitRoundTrip(f *testing.F) {
+	f.Add(uint64(0), uint64(1), uint8(1), uint8(64), uint8(3), false)
+	f.Add(uint64(0xdeadbeef), uint64(^uint64(0)), uint8(17), uint8(63), uint8(0), true)
+
+	f.Fuzz(func(t *testing.T, a, b uint64, wa, wb, lead uint8, lsb bool) {
+		order := MSBFirst
+		if lsb {
+			order = LSBFirst
+		}
+		// every width from 1 to 64 is reachable, preceded by 0..7 bits of misalignment
+		widthA := int(wa%64) + 1
+		widthB := int(wb%64) + 1
+		skip := int(lead % 8)
+
+		w := NewBitWriter(order)
+		w.WriteBits(0, skip)
+		w.WriteBits(a, widthA)
+		w.WriteBits(b, widthB)
+		w.Align()
+
+		if w.Len()%8 != 0 || w.Len() != len(w.Bytes())*8 {
+			t.Fatalf("misaligned output: %d bits, %d bytes", w.Len(), len(w.Bytes()))
+		}
+
+		r := NewBitReader(w.Bytes(), order)
+		if err := r.SkipBits(skip); err != nil {
+			t.Fatal(err)
+		}
+		gotA, err := r.ReadBits(widthA)
+		if err != nil || gotA != a&lowMask(widthA) {
+			t.Fatalf("width %d: expected = %x, got = %x (%v)", widthA, a&lowMask(widthA), gotA, err)
+		}
+		gotB, err := r.ReadBits(widthB)
+		if err != nil || gotB != b&lowMask(widthB) {
+			t.Fatalf("width %d: expected = %x, got = %x (%v)", widthB, b&lowMask(widthB), gotB, err)
+		}
+	})
+}
+
+func TestBitRoundTripAllWidths(t *testing.T) {
+	for _, order := range []BitOrder{MSBFirst, LSBFirst} {
+		w := NewBitWriter(order)
+		for n := 1; n <= 64; n++ {
+			w.WriteBits(^uint64(0)-uint64(n), n)
+		}
+
+		r := NewBitReader(w.Bytes(), order)
+		for n := 1; n <= 64; n++ {
+			got, err := r.ReadBits(n)
+			if want := (^uint64(0) - uint64(n)) & lowMask(n); err != nil || got != want {
+				t.Errorf("order %d width %d: expected = %x, got = %x (%v)", order, n, want, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package bytes
+
+import (
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"math/bits"
+	"sync"
+)
+
+// Hash8 is the common interface implemented by all 8-bit checksums.
+type Hash8 interface {
+	hash.Hash
+	Sum8() uint8
+}
+
+// Hash16 is the common interface implemented by all 16-bit checksums.
+type Hash16 interface {
+	hash.Hash
+	Sum16() uint16
+}
+
+// CRC8Params describes an 8-bit CRC using the Rocksoft model. Init and Poly
+// are given in normal (non-reflected) form; Reflect reverses both input bytes
+// and the final register.
+type CRC8Params struct {
+	Poly    uint8
+	Init    uint8
+	XorOut  uint8
+	Reflect bool
+}
+
+// CRC16Params describes a 16-bit CRC using the Rocksoft model.
+// It follows the same conventions as CRC8Params.
+type CRC16Params struct {
+	Poly    uint16
+	Init    uint16
+	XorOut  uint16
+	Reflect bool
+}
+
+// Common CRC-8 variants. The check value is the CRC of "123456789".
+var (
+	CRC8SMBus     = CRC8Params{Poly: 0x07}                           // check 0xF4
+	CRC8Maxim     = CRC8Params{Poly: 0x31, Reflect: true}            // 1-Wire, check 0xA1
+	CRC8CDMA2000  = CRC8Params{Poly: 0x9b, Init: 0xff}               // check 0xDA
+	CRC8DVBS2     = CRC8Params{Poly: 0xd5}                           // check 0xBC
+	CRC8AUTOSAR   = CRC8Params{Poly: 0x2f, Init: 0xff, XorOut: 0xff} // check 0xDF
+	CRC8SAEJ1850  = CRC8Params{Poly: 0x1d, Init: 0xff, XorOut: 0xff} // check 0x4B
+	CRC8Bluetooth = CRC8Params{Poly: 0xa7, Reflect: true}            // check 0x26
+)
+
+// Common CRC-16 variants. The check value is the CRC of "123456789".
+var (
+	CRC16CCITT  = CRC16Params{Poly: 0x1021, Init: 0xffff}                // CCITT-FALSE / IBM-3740, check 0x29B1
+	CRC16Kermit = CRC16Params{Poly: 0x1021, Reflect: true}               // true CCITT, check 0x2189
+	CRC16XModem = CRC16Params{Poly: 0x1021}                              // check 0x31C3
+	CRC16Modbus = CRC16Params{Poly: 0x8005, Init: 0xffff, Reflect: true} // check 0x4B37
+	CRC16ARC    = CRC16Params{Poly: 0x8005, Reflect: true}               // check 0xBB3D
+)
+
+var crcTables sync.Map // CRC8Params or CRC16Params -> *[256]uint16
+
+func crcTable(width int, poly uint16, reflect bool, key any) *[256]uint16 {
+	if t, ok := crcTables.Load(key); ok {
+		return t.(*[256]uint16)
+	}
+
+	t := new([256]uint16)
+	top := uint16(1) << (width - 1)
+	rpoly := bits.Reverse16(poly) >> (16 - width)
+	for i := range t {
+		c := uint16(i)
+		if reflect {
+			for range 8 {
+				if c&1 != 0 {
+					c = c>>1 ^ rpoly
+				} else {
+					c >>= 1
+				}
+			}
+		} else {
+			c <<= width - 8
+			for range 8 {
+				if c&top != 0 {
+					c = c<<1 ^ poly
+				} else {
+					c <<= 1
+				}
+			}
+		}
+		if width == 8 {
+			c &= 0xff
+		}
+		t[i] = c
+	}
+
+	actual, _ := crcTables.LoadOrStore(key, t)
+	return actual.(*[256]uint16)
+}
+
+type crc8 struct {
+	params CRC8Params
+	table  *[256]uint16
+	crc    uint8
+}
+
+// NewCRC8 returns a streaming CRC-8 with the given parameters.
+//
+// Usage:
+//
+//	h := NewCRC8(CRC8Maxim)
+//	h.Write(frame)
+//	sum := h.Sum8()
+func NewCRC8(p CRC8Params) Hash8 {
+	h := &crc8{params: p, table: crcTable(8, uint16(p.Poly), p.Reflect, p)}
+	h.Reset()
+	return h
+}
+
+func (h *crc8) Reset() {
+	h.crc = h.params.Init
+	if h.params.Reflect {
+		h.crc = bits.Reverse8(h.crc)
+	}
+}
+
+func (h *crc8) Write(p []byte) (int, error) {
+	for _, b := range p {
+		h.crc = uint8(h.table[h.crc^b])
+	}
+	return len(p), nil
+}
+
+func (h *crc8) Sum8() uint8         { return h.crc ^ h.params.XorOut }
+func (h *crc8) Sum(b []byte) []byte { return append(b, h.Sum8()) }
+func (h *crc8) Size() int           { return 1 }
+func (h *crc8) BlockSize() int      { return 1 }
+
+// ChecksumCRC8 returns the CRC-8 of data using the given parameters.
+func ChecksumCRC8(data []byte, p CRC8Params) uint8 {
+	h := NewCRC8(p)
+	h.Write(data)
+	return h.Sum8()
+}
+
+type crc16 struct {
+	params CRC16Params
+	table  *[256]uint16
+	crc    uint16
+}
+
+// NewCRC16 returns a streaming CRC-16 with the given parameters.
+// Sum appends the checksum big-endian; Modbus RTU frames transmit it
+// little-endian, so compare against Sum16 when verifying them.
+func NewCRC16(p CRC16Params) Hash16 {
+	h := &crc16{params: p, table: crcTable(16, p.Poly, p.Reflect, p)}
+	h.Reset()
+	return h
+}
+
+func (h *crc16) Reset() {
+	h.crc = h.params.Init
+	if h.params.Reflect {
+		h.crc = bits.Reverse16(h.crc)
+	}
+}
+
+func (h *crc16) Write(p []byte) (int, error) {
+	if h.params.Reflect {
+		for _, b := range p {
+			h.crc = h.table[byte(h.crc)^b] ^ h.crc>>8
+		}
+	} else {
+		for _, b := range p {
+			h.crc = h.table[byte(h.crc>>8)^b] ^ h.crc<<8
+		}
+	}
+	return len(p), nil
+}
+
+func (h *crc16) Sum16() uint16 { return h.crc ^ h.params.XorOut }
+func (h *crc16) Sum(b []byte) []byte {
+	s := h.Sum16()
+	return append(b, byte(s>>8), byte(s))
+}
+func (h *crc16) Size() int      { return 2 }
+func (h *crc16) BlockSize() int { return 1 }
+
+// ChecksumCRC16 returns the CRC-16 of data using the given parameters.
+func ChecksumCRC16(data []byte, p CRC16Params) uint16 {
+	h := NewCRC16(p)
+	h.Write(data)
+	return h.Sum16()
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// NewCRC32C returns a streaming CRC-32C (Castagnoli), as used by iSCSI,
+// SCTP and ext4. It uses hardware acceleration when available.
+func NewCRC32C() hash.Hash32 {
+	return crc32.New(castagnoli)
+}
+
+// ChecksumCRC32C returns the CRC-32C of data.
+func ChecksumCRC32C(data []byte) uint32 {
+	return crc32.Checksum(data, castagnoli)
+}
+
+// NewAdler32 returns a streaming Adler-32 checksum as defined in RFC 1950.
+func NewAdler32() hash.Hash32 {
+	return adler32.New()
+}
+
+// ChecksumAdler32 returns the Adler-32 checksum of data.
+func ChecksumAdler32(data []byte) uint32 {
+	return adler32.Checksum(data)
+}
+
+type fletcher16 struct {
+	sum1, sum2 uint32
+}
+
+// NewFletcher16 returns a streaming Fletcher-16 checksum computed over bytes.
+func NewFletcher16() Hash16 {
+	return &fletcher16{}
+}
+
+func (h *fletcher16) Reset() { h.sum1, h.sum2 = 0, 0 }
+
+func (h *fletcher16) Write(p []byte) (int, error) {
+	for _, b := range p {
+		h.sum1 = (h.sum1 + uint32(b)) % 255
+		h.sum2 = (h.sum2 + h.sum1) % 255
+	}
+	return len(p), nil
+}
+
+func (h *fletcher16) Sum16() uint16 { return uint16(h.sum2<<8 | h.sum1) }
+func (h *fletcher16) Sum(b []byte) []byte {
+	s := h.Sum16()
+	return append(b, byte(s>>8), byte(s))
+}
+func (h *fletcher16) Size() int      { return 2 }
+func (h *fletcher16) BlockSize() int { return 1 }
+
+// ChecksumFletcher16 returns the Fletcher-16 checksum of data.
+func ChecksumFletcher16(data []byte) uint16 {
+	h := NewFletcher16()
+	h.Write(data)
+	return h.Sum16()
+}
+
+type fletcher32 struct {
+	sum1, sum2 uint32
+	pending    byte
+	odd        bool
+}
+
+// NewFletcher32 returns a streaming Fletcher-32 checksum computed over
+// little-endian 16-bit words. An odd trailing byte is padded with zero.
+func NewFletcher32() hash.Hash32 {
+	return &fletcher32{}
+}
+
+func (h *fletcher32) Reset() { *h = fletcher32{} }
+
+func (h *fletcher32) word(w uint32) {
+	h.sum1 = (h.sum1 + w) % 65535
+	h.sum2 = (h.sum2 + h.sum1) % 65535
+}
+
+func (h *fletcher32) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if h.odd {
+			h.word(uint32(h.pending) | uint32(b)<<8)
+		} else {
+			h.pending = b
+		}
+		h.odd = !h.odd
+	}
+	return len(p), nil
+}
+
+func (h *fletcher32) Sum32() uint32 {
+	s1, s2 := h.sum1, h.sum2
+	if h.odd {
+		s1 = (s1 + uint32(h.pending)) % 65535
+		s2 = (s2 + s1) % 65535
+	}
+	return s2<<16 | s1
+}
+func (h *fletcher32) Sum(b []byte) []byte {
+	s := h.Sum32()
+	return append(b, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+func (h *fletcher32) Size() int      { return 4 }
+func (h *fletcher32) BlockSize() int { return 2 }
+
+// ChecksumFletcher32 returns the Fletcher-32 checksum of data.
+func ChecksumFletcher32(data []byte) uint32 {
+	h := NewFletcher32()
+	h.Write(data)
+	return h.Sum32()
+}
+
+type xor8 struct {
+	sum uint8
+	lrc bool
+}
+
+// NewXOR returns a streaming checksum that XORs every byte together,
+// as used by NMEA 0183 sentences.
+func NewXOR() Hash8 {
+	return &xor8{}
+}
+
+// NewLRC returns a streaming longitudinal redundancy check: the two's
+// complement of the 8-bit sum of all bytes, as used by Modbus ASCII.
+func NewLRC() Hash8 {
+	return &xor8{lrc: true}
+}
+
+func (h *xor8) Reset() { h.sum = 0 }
+
+func (h *xor8) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if h.lrc {
+			h.sum += b
+		} else {
+			h.sum ^= b
+		}
+	}
+	return len(p), nil
+}
+
+func (h *xor8) Sum8() uint8 {
+	if h.lrc {
+		return -h.sum
+	}
+	return h.sum
+}
+func (h *xor8) Sum(b []byte) []byte { return append(b, h.Sum8()) }
+func (h *xor8) Size() int           { return 1 }
+func (h *xor8) BlockSize() int      { return 1 }
+
+// ChecksumXOR returns the XOR of all bytes in data.
+func ChecksumXOR(data []byte) uint8 {
+	h := NewXOR()
+	h.Write(data)
+	return h.Sum8()
+}
+
+// ChecksumLRC returns the Modbus ASCII longitudinal redundancy check of data.
+func ChecksumLRC(data []byte) uint8 {
+	h := NewLRC()
+	h.Write(data)
+	return h.Sum8()
+}
@@ -0,0 +1,129 @@
+package bytes
+
+import (
+	stdbytes "bytes"
+	"hash"
+	"testing"
+)
+
+var checkInput = []byte("123456789")
+
+func TestCRC8CheckValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   CRC8Params
+		expected uint8
+	}{
+		{"SMBUS", CRC8SMBus, 0xf4},
+		{"MAXIM-DOW", CRC8Maxim, 0xa1},
+		{"CDMA2000", CRC8CDMA2000, 0xda},
+		{"DVB-S2", CRC8DVBS2, 0xbc},
+		{"AUTOSAR", CRC8AUTOSAR, 0xdf},
+		{"SAE-J1850", CRC8SAEJ1850, 0x4b},
+		{"BLUETOOTH", CRC8Bluetooth, 0x26},
+	}
+
+	for _, tt := range tests {
+		if got := ChecksumCRC8(checkInput, tt.params); got != tt.expected {
+			t.Errorf("CRC-8/%s: expected = %#02x, got = %#02x", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestCRC16CheckValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   CRC16Params
+		expected uint16
+	}{
+		{"IBM-3740", CRC16CCITT, 0x29b1},
+		{"KERMIT", CRC16Kermit, 0x2189},
+		{"XMODEM", CRC16XModem, 0x31c3},
+		{"MODBUS", CRC16Modbus, 0x4b37},
+		{"ARC", CRC16ARC, 0xbb3d},
+	}
+
+	for _, tt := range tests {
+		if got := ChecksumCRC16(checkInput, tt.params); got != tt.expected {
+			t.Errorf("CRC-16/%s: expected = %#04x, got = %#04x", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestChecksumCheckValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		got      uint32
+		expected uint32
+	}{
+		{"CRC-32C", "123456789", ChecksumCRC32C(checkInput), 0xe3069283},
+		{"Adler-32", "123456789", ChecksumAdler32(checkInput), 0x091e01de},
+		{"Fletcher-16", "abcde", uint32(ChecksumFletcher16([]byte("abcde"))), 0xc8f0},
+		{"Fletcher-16", "abcdef", uint32(ChecksumFletcher16([]byte("abcdef"))), 0x2057},
+		{"Fletcher-32", "abcde", ChecksumFletcher32([]byte("abcde")), 0xf04fc729},
+		{"Fletcher-32", "abcdef", ChecksumFletcher32([]byte("abcdef")), 0x56502d2a},
+		{"Fletcher-32", "abcdefgh", ChecksumFletcher32([]byte("abcdefgh")), 0xebe19591},
+		{"XOR", "123456789", uint32(ChecksumXOR(checkInput)), 0x31},
+		{"LRC", "123456789", uint32(ChecksumLRC(checkInput)), 0x23},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s(%q): expected = %#x, got = %#x", tt.name, tt.input, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestModbusFrame(t *testing.T) {
+	// read holding registers request, CRC transmitted low byte first
+	frame := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0a, 0xc5, 0xcd}
+
+	crc := ChecksumCRC16(frame[:6], CRC16Modbus)
+	if byte(crc) != frame[6] || byte(crc>>8) != frame[7] {
+		t.Errorf("incorrect Modbus CRC, expected = % x, got = %#04x", frame[6:], crc)
+	}
+
+	// running the CRC over a valid frame including its CRC yields zero
+	if got := ChecksumCRC16(frame, CRC16Modbus); got != 0 {
+		t.Errorf("expected zero residue, got = %#04x", got)
+	}
+}
+
+func TestChecksumStreaming(t *testing.T) {
+	hashes := map[string]func() hash.Hash{
+		"CRC-8":       func() hash.Hash { return NewCRC8(CRC8Maxim) },
+		"CRC-16":      func() hash.Hash { return NewCRC16(CRC16Modbus) },
+		"CRC-32C":     func() hash.Hash { return NewCRC32C() },
+		"Adler-32":    func() hash.Hash { return NewAdler32() },
+		"Fletcher-16": func() hash.Hash { return NewFletcher16() },
+		"Fletcher-32": func() hash.Hash { return NewFletcher32() },
+		"XOR":         func() hash.Hash { return NewXOR() },
+		"LRC":         func() hash.Hash { return NewLRC() },
+	}
+	data := []byte("The quick brown fox jumps over the lazy dog")
+
+	for name, newHash := range hashes {
+		whole := newHash()
+		whole.Write(data)
+		expected := whole.Sum(nil)
+
+		// odd chunk sizes exercise the Fletcher-32 pending byte
+		chunked := newHash()
+		for i := 0; i < len(data); i += 3 {
+			chunked.Write(data[i:min(i+3, len(data))])
+		}
+		if got := chunked.Sum(nil); !stdbytes.Equal(got, expected) {
+			t.Errorf("%s: chunked sum differs, expected = %x, got = %x", name, expected, got)
+		}
+		if len(expected) != whole.Size() {
+			t.Errorf("%s: Sum length %d does not match Size %d", name, len(expected), whole.Size())
+		}
+
+		whole.Reset()
+		whole.Write(data)
+		if got := whole.Sum(nil); !stdbytes.Equal(got, expected) {
+			t.Errorf("%s: sum after Reset differs, expected = %x, got = %x", name, expected, got)
+		}
+	}
+}
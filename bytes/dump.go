@@ -0,0 +1,520 @@
+package bytes
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Range is a half-open interval [Start, End) of byte offsets.
+type Range struct {
+	Start, End int
+}
+
+// DumpOptions configures Dump and Diff. The zero value produces the same
+// layout as `hexdump -C`.
+type DumpOptions struct {
+	// Width is the number of bytes per row. Defaults to 16.
+	Width int
+	// Group is the number of bytes between extra spaces. Defaults to 8,
+	// a negative value disables grouping.
+	Group int
+	// Offset is added to the printed offsets, useful when dumping a window
+	// of a larger buffer.
+	Offset int
+	// NoASCII hides the ASCII column.
+	NoASCII bool
+	// Highlight lists byte ranges to emphasize, relative to the dumped data.
+	// The ranges may overlap and come in any order.
+	Highlight []Range
+	// MarkStart and MarkEnd wrap highlighted bytes. They default to ANSI
+	// reverse video.
+	MarkStart, MarkEnd string
+	// Plain disables MarkStart and MarkEnd, for output that is not sent to
+	// a terminal.
+	Plain bool
+}
+
+func (o *DumpOptions) withDefaults() DumpOptions {
+	var d DumpOptions
+	if o != nil {
+		d = *o
+	}
+	if d.Width <= 0 {
+		d.Width = 16
+	}
+	if d.Group == 0 {
+		d.Group = 8
+	}
+	if d.Plain {
+		d.MarkStart, d.MarkEnd = "", ""
+	} else if d.MarkStart == "" && d.MarkEnd == "" {
+		d.MarkStart, d.MarkEnd = "\x1b[7m", "\x1b[0m"
+	}
+	return d
+}
+
+// highlighter reports which offsets fall in a set of ranges. It keeps the
+// ranges sorted and merged, and a cursor on the first one that does not end
+// before the last offset asked for, so offsets must be asked in increasing
+// order. A whole dump then costs one pass over the bytes and the ranges.
+type highlighter struct {
+	ranges []Range
+	cur    int
+}
+
+func newHighlighter(ranges []Range) *highlighter {
+	sorted := make([]Range, 0, len(ranges))
+	for _, r := range ranges {
+		if r.End > r.Start {
+			sorted = append(sorted, r)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b Range) int { return cmp.Compare(a.Start, b.Start) })
+
+	merged := sorted[:0]
+	for _, r := range sorted {
+		if k := len(merged) - 1; k >= 0 && r.Start <= merged[k].End {
+			merged[k].End = max(merged[k].End, r.End)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return &highlighter{ranges: merged}
+}
+
+// at reports whether offset i is highlighted. i must not be smaller than in
+// the previous call.
+func (h *highlighter) at(i int) bool {
+	for h.cur < len(h.ranges) && h.ranges[h.cur].End <= i {
+		h.cur++
+	}
+	return h.cur < len(h.ranges) && h.ranges[h.cur].Start <= i
+}
+
+func printable(b byte) byte {
+	if b < 0x20 || b > 0x7e {
+		return '.'
+	}
+	return b
+}
+
+// cell describes a single byte position in a row. missing marks positions
+// past the end of a buffer when diffing buffers of different lengths.
+type cell struct {
+	b       byte
+	missing bool
+	mark    bool
+}
+
+// writeRow renders the hex and ASCII columns of one row.
+func writeRow(sb *strings.Builder, cells []cell, o *DumpOptions) {
+	for i := range o.Width {
+		if i >= len(cells) && o.NoASCII {
+			break
+		}
+		if i > 0 {
+			sb.WriteByte(' ')
+			if o.Group > 0 && i%o.Group == 0 {
+				sb.WriteByte(' ')
+			}
+		}
+		switch {
+		case i >= len(cells):
+			sb.WriteString("  ")
+		case cells[i].missing:
+			sb.WriteString(wrap("--", cells[i].mark, o))
+		default:
+			sb.WriteString(wrap(fmt.Sprintf("%02x", cells[i].b), cells[i].mark, o))
+		}
+	}
+
+	if o.NoASCII {
+		return
+	}
+	sb.WriteString("  |")
+	for _, c := range cells {
+		ch := " "
+		if !c.missing {
+			ch = string(printable(c.b))
+		}
+		sb.WriteString(wrap(ch, c.mark, o))
+	}
+	sb.WriteByte('|')
+}
+
+func wrap(s string, mark bool, o *DumpOptions) string {
+	if !mark || o.Plain {
+		return s
+	}
+	return o.MarkStart + s + o.MarkEnd
+}
+
+// Dump formats data as a hex and ASCII listing.
+//
+// Parameters:
+//   - data: The bytes to dump.
+//   - opts: Layout options, nil for the defaults.
+//
+// Returns:
+//   - One line per row, each terminated by a newline.
+//
+// Example output:
+//
+//	00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a        |Hello, world!.|
+func Dump(data []byte, opts *DumpOptions) string {
+	o := opts.withDefaults()
+	hl := newHighlighter(o.Highlight)
+	var sb strings.Builder
+
+	cells := make([]cell, 0, o.Width)
+	for start := 0; start < len(data); start += o.Width {
+		end := min(start+o.Width, len(data))
+		cells = cells[:0]
+		for i := start; i < end; i++ {
+			cells = append(cells, cell{b: data[i], mark: hl.at(i)})
+		}
+
+		fmt.Fprintf(&sb, "%08x  ", o.Offset+start)
+		writeRow(&sb, cells, &o)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// DiffRanges compares a and b position by position, like cmp, and returns
+// the ranges where they differ. Bytes past the end of the shorter buffer
+// count as different. It suits buffers of a fixed layout; see DiffEdits for
+// buffers where bytes may have been inserted or removed.
+func DiffRanges(a, b []byte) []Range {
+	var ranges []Range
+	n := max(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if i < len(a) && i < len(b) && a[i] == b[i] {
+			continue
+		}
+		if k := len(ranges) - 1; k >= 0 && ranges[k].End == i {
+			ranges[k].End++
+		} else {
+			ranges = append(ranges, Range{i, i + 1})
+		}
+	}
+	return ranges
+}
+
+// EditKind is the kind of an Edit.
+type EditKind int
+
+const (
+	// EditEqual is a run of bytes present in both buffers.
+	EditEqual EditKind = iota
+	// EditChange is a run of bytes of a replaced by as many bytes of b.
+	EditChange
+	// EditDelete is a run of bytes of a missing from b.
+	EditDelete
+	// EditInsert is a run of bytes of b missing from a.
+	EditInsert
+)
+
+// Edit is a run of the alignment of two buffers. A and B are the bytes it
+// covers in each buffer; A is empty for an insertion and B for a deletion,
+// at the offset where the bytes would be.
+type Edit struct {
+	Kind EditKind
+	A, B Range
+}
+
+// Limits of the alignment in DiffEdits: the number of inserted and deleted
+// bytes it looks for, and a bound on its running time, proportional to the
+// product of that number and the size of the buffers.
+const (
+	maxAlignEdits = 1024
+	maxAlignWork  = 1 << 26
+)
+
+// DiffEdits aligns a and b and returns the edits that turn a into b, as
+// runs that cover both buffers in order. It finds a shortest edit script
+// with the Myers algorithm, so a byte inserted into or removed from b is
+// reported as such, and the bytes after it still match. Deleted bytes
+// followed by as many inserted ones are reported as changed.
+//
+// Aligning costs time proportional to the size of the buffers times the
+// number of edits. When the buffers differ by more than about a thousand
+// bytes, or fewer for large buffers, DiffEdits falls back to comparing them
+// by position, as DiffRanges does.
+//
+// Returns:
+//   - The edits, nil if both buffers are empty.
+func DiffEdits(a, b []byte) []Edit {
+	// the common prefix and suffix need no alignment
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+
+	ops, ok := alignBytes(ma, mb)
+	if !ok {
+		ops = positionalOps(ma, mb)
+	}
+
+	var edits []Edit
+	add := func(k EditKind, ai, an, bi, bn int) {
+		if an == 0 && bn == 0 {
+			return
+		}
+		edits = append(edits, Edit{k, Range{ai, ai + an}, Range{bi, bi + bn}})
+	}
+	add(EditEqual, 0, pre, 0, pre)
+
+	// group the operations into runs of equal bytes and hunks of deleted
+	// and inserted ones, pairing the deleted and inserted bytes of a hunk
+	// as changes
+	ai, bi := pre, pre
+	for i := 0; i < len(ops); {
+		j := i
+		if ops[i] == EditEqual {
+			for j < len(ops) && ops[j] == EditEqual {
+				j++
+			}
+			add(EditEqual, ai, j-i, bi, j-i)
+			ai, bi, i = ai+j-i, bi+j-i, j
+			continue
+		}
+		del, ins := 0, 0
+		for ; j < len(ops) && ops[j] != EditEqual; j++ {
+			if ops[j] == EditDelete {
+				del++
+			} else {
+				ins++
+			}
+		}
+		c := min(del, ins)
+		add(EditChange, ai, c, bi, c)
+		add(EditDelete, ai+c, del-c, bi+c, 0)
+		add(EditInsert, ai+c, 0, bi+c, ins-c)
+		ai, bi, i = ai+del, bi+ins, j
+	}
+	add(EditEqual, len(a)-suf, suf, len(b)-suf, suf)
+	return edits
+}
+
+// positionalOps compares a and b by position, as one EditDelete and one
+// EditInsert for each differing byte.
+func positionalOps(a, b []byte) []EditKind {
+	ops := make([]EditKind, 0, len(a)+len(b))
+	for i := range min(len(a), len(b)) {
+		if a[i] == b[i] {
+			ops = append(ops, EditEqual)
+		} else {
+			ops = append(ops, EditDelete, EditInsert)
+		}
+	}
+	for range len(a) - min(len(a), len(b)) {
+		ops = append(ops, EditDelete)
+	}
+	for range len(b) - min(len(a), len(b)) {
+		ops = append(ops, EditInsert)
+	}
+	return ops
+}
+
+// alignBytes finds a shortest edit script from a to b with the Myers
+// algorithm, one operation per byte, or reports false when it takes more
+// edits than the limits allow.
+func alignBytes(a, b []byte) ([]EditKind, bool) {
+	n, m := len(a), len(b)
+	maxD := min(maxAlignEdits, n+m, maxAlignWork/max(n+m, 1))
+
+	// v[off+k] is the furthest x reached on diagonal k = x-y; trace keeps
+	// its diagonals -d..d after each round d for the way back
+	off := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1] // down: insert b[y]
+			} else {
+				x = v[off+k-1] + 1 // right: delete a[x]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, slices.Clone(v[off-d:off+d+1]))
+				return backtrack(trace, n, m), true
+			}
+		}
+		trace = append(trace, slices.Clone(v[off-d:off+d+1]))
+	}
+	return nil, false
+}
+
+// backtrack walks the rounds of alignBytes back from (n, m) and returns the
+// operations in order.
+func backtrack(trace [][]int, n, m int) []EditKind {
+	ops := make([]EditKind, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1] // diagonals -(d-1)..d-1
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var pk int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := at(pk)
+		py := px - pk
+		// the edit moved from (px, py) to (xs, ys), then a snake of equal
+		// bytes followed up to (x, y)
+		op, xs := EditInsert, px
+		if pk == k-1 {
+			op, xs = EditDelete, px+1
+		}
+		for range x - xs {
+			ops = append(ops, EditEqual)
+		}
+		ops = append(ops, op)
+		x, y = px, py
+	}
+	for range x { // the snake of round 0
+		ops = append(ops, EditEqual)
+	}
+	slices.Reverse(ops)
+	return ops
+}
+
+// Diff aligns a and b with DiffEdits and renders every row that contains a
+// difference, with a marker line under the differing bytes. Rows without
+// differences are elided. Each row shows the bytes of a above the bytes of
+// b they are aligned with, and each side has its own offsets. Bytes deleted
+// from a or inserted into b are shown as "--" on the other side. Highlight
+// in opts is ignored; unless Plain is set, MarkStart and MarkEnd also wrap
+// the differing bytes.
+//
+// Returns:
+//   - An empty string if a and b are equal, otherwise a summary line followed
+//     by the differing rows.
+func Diff(a, b []byte, opts *DumpOptions) string {
+	edits := DiffEdits(a, b)
+	var changed, deleted, inserted int
+	first := -1
+	for _, e := range edits {
+		if e.Kind != EditEqual && first < 0 {
+			first = e.A.Start
+		}
+		switch e.Kind {
+		case EditChange:
+			changed += e.A.End - e.A.Start
+		case EditDelete:
+			deleted += e.A.End - e.A.Start
+		case EditInsert:
+			inserted += e.B.End - e.B.Start
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+	o := opts.withDefaults()
+
+	// the aligned columns: a byte of a, of b, or both; -1 when missing
+	type column struct {
+		ai, bi int
+		mark   bool
+	}
+	var cols []column
+	for _, e := range edits {
+		an, bn := e.A.End-e.A.Start, e.B.End-e.B.Start
+		for i := range max(an, bn) {
+			c := column{ai: -1, bi: -1, mark: e.Kind != EditEqual}
+			if i < an {
+				c.ai = e.A.Start + i
+			}
+			if i < bn {
+				c.bi = e.B.Start + i
+			}
+			cols = append(cols, c)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d byte(s) differ (%d changed, %d deleted, %d inserted), first at offset %#x (len %d vs %d)\n",
+		changed+deleted+inserted, changed, deleted, inserted, o.Offset+first, len(a), len(b))
+
+	row := func(buf []byte, cs []column, b bool) []cell {
+		cells := make([]cell, 0, len(cs))
+		for _, c := range cs {
+			i := c.ai
+			if b {
+				i = c.bi
+			}
+			if i < 0 {
+				cells = append(cells, cell{missing: true, mark: c.mark})
+			} else {
+				cells = append(cells, cell{b: buf[i], mark: c.mark})
+			}
+		}
+		return cells
+	}
+
+	// offsets of the first byte of each side in the current row
+	aOff, bOff := 0, 0
+	last := -1
+	for start := 0; start < len(cols); start += o.Width {
+		cs := cols[start:min(start+o.Width, len(cols))]
+		rowA, rowB := aOff, bOff
+		for _, c := range cs {
+			if c.ai >= 0 {
+				aOff++
+			}
+			if c.bi >= 0 {
+				bOff++
+			}
+		}
+		if !slices.ContainsFunc(cs, func(c column) bool { return c.mark }) {
+			continue
+		}
+		if last >= 0 && start != last+o.Width {
+			sb.WriteString("...\n")
+		}
+		last = start
+
+		fmt.Fprintf(&sb, "%08x a: ", o.Offset+rowA)
+		writeRow(&sb, row(a, cs, false), &o)
+		sb.WriteByte('\n')
+		fmt.Fprintf(&sb, "%08x b: ", o.Offset+rowB)
+		writeRow(&sb, row(b, cs, true), &o)
+		sb.WriteByte('\n')
+
+		// marker line, aligned under the hex column
+		var marker strings.Builder
+		marker.WriteString(strings.Repeat(" ", 12))
+		for col, c := range cs {
+			if col > 0 {
+				marker.WriteByte(' ')
+				if o.Group > 0 && col%o.Group == 0 {
+					marker.WriteByte(' ')
+				}
+			}
+			if c.mark {
+				marker.WriteString("^^")
+			} else {
+				marker.WriteString("  ")
+			}
+		}
+		sb.WriteString(strings.TrimRight(marker.String(), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
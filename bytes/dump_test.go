@@ -0,0 +1,260 @@
+package bytes
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDumpDefault(t *testing.T) {
+	data := []byte("Hello, world!\n")
+	expected := "00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a        |Hello, world!.|\n"
+
+	if got := Dump(data, nil); got != expected {
+		t.Errorf("incorrect dump\nexpected = %q\ngot      = %q", expected, got)
+	}
+}
+
+func TestDumpOptions(t *testing.T) {
+	data := []byte{0x00, 0x41, 0x42, 0xff, 0x43}
+	opts := &DumpOptions{
+		Width:     4,
+		Group:     2,
+		Offset:    0x100,
+		Highlight: []Range{{1, 3}},
+		MarkStart: "[",
+		MarkEnd:   "]",
+	}
+	expected := "" +
+		"00000100  00 [41]  [42] ff  |.[A][B].|\n" +
+		"00000104  43            |C|\n"
+
+	if got := Dump(data, opts); got != expected {
+		t.Errorf("incorrect dump\nexpected = %q\ngot      = %q", expected, got)
+	}
+
+	opts = &DumpOptions{Width: 4, Group: -1, NoASCII: true}
+	expected = "00000000  00 41 42 ff\n00000004  43\n"
+	if got := Dump(data, opts); got != expected {
+		t.Errorf("incorrect dump without ASCII\nexpected = %q\ngot      = %q", expected, got)
+	}
+}
+
+func TestDumpHighlightOrder(t *testing.T) {
+	data := []byte("abcdefgh")
+	opts := &DumpOptions{
+		Width:     8,
+		Group:     -1,
+		NoASCII:   true,
+		Highlight: []Range{{6, 7}, {2, 4}, {3, 5}, {0, 0}, {1, 2}},
+		MarkStart: "[",
+		MarkEnd:   "]",
+	}
+	expected := "00000000  61 [62] [63] [64] [65] 66 [67] 68\n"
+	if got := Dump(data, opts); got != expected {
+		t.Errorf("incorrect dump\nexpected = %q\ngot      = %q", expected, got)
+	}
+}
+
+func TestDiffRanges(t *testing.T) {
+	a := []byte{1, 2, 3, 4, 5, 6}
+	b := []byte{1, 0, 0, 4, 5, 0, 7}
+
+	ranges := DiffRanges(a, b)
+	expected := []Range{{1, 3}, {5, 7}}
+	if len(ranges) != len(expected) {
+		t.Fatalf("incorrect ranges, expected = %v, got = %v", expected, ranges)
+	}
+	for i := range ranges {
+		if ranges[i] != expected[i] {
+			t.Errorf("incorrect range, expected = %v, got = %v", expected[i], ranges[i])
+		}
+	}
+
+	if DiffRanges(a, a) != nil {
+		t.Errorf("expected no ranges for equal buffers")
+	}
+
+	// the comparison is positional: an inserted byte shifts the rest
+	inserted := []byte{1, 9, 2, 3, 4, 5, 6}
+	if got := DiffRanges(a, inserted); len(got) != 1 || got[0] != (Range{1, 7}) {
+		t.Errorf("expected the rest of the buffer to differ, got %v", got)
+	}
+}
+
+func TestDiffEdits(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected []Edit
+	}{
+		{"", "", nil},
+		{"abc", "abc", []Edit{{EditEqual, Range{0, 3}, Range{0, 3}}}},
+		{"abcdef", "abXdef", []Edit{
+			{EditEqual, Range{0, 2}, Range{0, 2}},
+			{EditChange, Range{2, 3}, Range{2, 3}},
+			{EditEqual, Range{3, 6}, Range{3, 6}},
+		}},
+		{"abcdef", "abXYcdef", []Edit{
+			{EditEqual, Range{0, 2}, Range{0, 2}},
+			{EditInsert, Range{2, 2}, Range{2, 4}},
+			{EditEqual, Range{2, 6}, Range{4, 8}},
+		}},
+		{"abcdef", "abdef", []Edit{
+			{EditEqual, Range{0, 2}, Range{0, 2}},
+			{EditDelete, Range{2, 3}, Range{2, 2}},
+			{EditEqual, Range{3, 6}, Range{2, 5}},
+		}},
+		{"abc", "", []Edit{{EditDelete, Range{0, 3}, Range{0, 0}}}},
+		{"", "ab", []Edit{{EditInsert, Range{0, 0}, Range{0, 2}}}},
+		{"xabcx", "yabcyy", []Edit{
+			{EditChange, Range{0, 1}, Range{0, 1}},
+			{EditEqual, Range{1, 4}, Range{1, 4}},
+			{EditChange, Range{4, 5}, Range{4, 5}},
+			{EditInsert, Range{5, 5}, Range{5, 6}},
+		}},
+	}
+	for _, tt := range tests {
+		if got := DiffEdits([]byte(tt.a), []byte(tt.b)); !slices.Equal(got, tt.expected) {
+			t.Errorf("DiffEdits(%q, %q)\nexpected = %v\ngot      = %v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+// lcsLen is the textbook dynamic program for the longest common subsequence.
+func lcsLen(a, b []byte) int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func TestDiffEditsShortest(t *testing.T) {
+	r := rand.New(rand.NewPCG(4, 2))
+	random := func() []byte {
+		buf := make([]byte, r.IntN(40))
+		for i := range buf {
+			buf[i] = byte('a' + r.IntN(3))
+		}
+		return buf
+	}
+	for range 500 {
+		a, b := random(), random()
+		edits := DiffEdits(a, b)
+
+		// the edits cover both buffers in order and rebuild b from a
+		var rebuilt []byte
+		ai, bi, edited := 0, 0, 0
+		for _, e := range edits {
+			if e.A.Start != ai || e.B.Start != bi {
+				t.Fatalf("DiffEdits(%q, %q): %v does not follow the previous edit", a, b, e)
+			}
+			if e.Kind == EditEqual && !slices.Equal(a[e.A.Start:e.A.End], b[e.B.Start:e.B.End]) {
+				t.Fatalf("DiffEdits(%q, %q): %v is not equal", a, b, e)
+			}
+			if e.Kind != EditEqual {
+				edited += e.A.End - e.A.Start + e.B.End - e.B.Start
+			}
+			rebuilt = append(rebuilt, b[e.B.Start:e.B.End]...)
+			ai, bi = e.A.End, e.B.End
+		}
+		if ai != len(a) || bi != len(b) || !slices.Equal(rebuilt, b) {
+			t.Fatalf("DiffEdits(%q, %q) does not cover the buffers: %v", a, b, edits)
+		}
+		// a change counts as a deletion and an insertion
+		if want := len(a) + len(b) - 2*lcsLen(a, b); edited != want {
+			t.Fatalf("DiffEdits(%q, %q) edits %d bytes, the shortest script edits %d", a, b, edited, want)
+		}
+	}
+}
+
+func TestDiffEditsFallback(t *testing.T) {
+	// unrelated buffers take far more edits than the alignment looks for,
+	// and are compared by position
+	r := rand.New(rand.NewPCG(9, 9))
+	a, b := make([]byte, 5000), make([]byte, 5000)
+	for i := range a {
+		a[i], b[i] = byte(r.Uint32()), byte(r.Uint32())
+	}
+	var got []Range
+	for _, e := range DiffEdits(a, b) {
+		if e.A != e.B || (e.Kind != EditEqual && e.Kind != EditChange) {
+			t.Fatalf("expected positional edits, got %v", e)
+		}
+		if e.Kind == EditChange {
+			got = append(got, e.A)
+		}
+	}
+	if !slices.Equal(got, DiffRanges(a, b)) {
+		t.Errorf("the fallback differs from DiffRanges")
+	}
+
+	// a shifted buffer is still aligned
+	shifted := append([]byte{0xee}, a...)
+	edits := DiffEdits(a, shifted)
+	if len(edits) != 2 || edits[0].Kind != EditInsert || edits[1].Kind != EditEqual {
+		t.Errorf("expected a single insertion, got %v", edits)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if Diff([]byte{1, 2}, []byte{1, 2}, nil) != "" {
+		t.Errorf("expected empty diff for equal buffers")
+	}
+
+	a := make([]byte, 40)
+	b := make([]byte, 41)
+	b[1] = 0xff
+	b[40] = 'x'
+
+	// one inserted byte and one changed byte, each side with its offsets
+	expected := "" +
+		"2 byte(s) differ (1 changed, 0 deleted, 1 inserted), first at offset 0x1 (len 40 vs 41)\n" +
+		"00000000 a: 00 -- 00 00  |. ..|\n" +
+		"00000000 b: 00 ff 00 00  |....|\n" +
+		"               ^^\n" +
+		"...\n" +
+		"00000027 a: 00           |.|\n" +
+		"00000028 b: 78           |x|\n" +
+		"            ^^\n"
+
+	got := Diff(a, b, &DumpOptions{Width: 4, Group: -1, Plain: true})
+	if got != expected {
+		t.Errorf("incorrect diff\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	// a removed byte only marks itself, not the rest of the buffer
+	a = []byte("0123456789abcdefghij")
+	b = slices.Delete(slices.Clone(a), 5, 6)
+	expected = "" +
+		"1 byte(s) differ (0 changed, 1 deleted, 0 inserted), first at offset 0x5 (len 20 vs 19)\n" +
+		"00000004 a: 34 35 36 37  |4567|\n" +
+		"00000004 b: 34 -- 36 37  |4 67|\n" +
+		"               ^^\n"
+	got = Diff(a, b, &DumpOptions{Width: 4, Group: -1, Plain: true})
+	if got != expected {
+		t.Errorf("incorrect diff\nexpected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func BenchmarkDiff(b *testing.B) {
+	// every other byte differs: many ranges over a large buffer
+	x := make([]byte, 1<<16)
+	y := make([]byte, len(x))
+	for i := 0; i < len(y); i += 2 {
+		y[i] = 1
+	}
+	for b.Loop() {
+		if !strings.Contains(Diff(x, y, nil), "differ") {
+			b.Fatal("expected a diff")
+		}
+	}
+}
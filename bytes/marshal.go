@@ -0,0 +1,472 @@
+package bytes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidTag is returned when a `bin` struct tag cannot be parsed or
+	// does not apply to the field type.
+	ErrInvalidTag = errors.New("bytes: invalid bin tag")
+	// ErrUnsupportedType is returned for Go types that have no binary layout.
+	ErrUnsupportedType = errors.New("bytes: unsupported type")
+	// ErrValueRange is returned by Marshal when a value does not fit in its
+	// declared width.
+	ErrValueRange = errors.New("bytes: value out of range for field width")
+)
+
+// fieldSpec is the parsed form of a `bin` struct tag.
+type fieldSpec struct {
+	kind     byte // 'u', 'i', 'f', 'b' or 0 when inferred from the Go type
+	size     int  // width in bytes, 0 when inferred
+	bits     int  // width in bits for packed fields, 0 for byte fields
+	little   bool
+	lenField string
+	skip     bool
+}
+
+func parseTag(tag string) (fieldSpec, error) {
+	var s fieldSpec
+	if tag == "-" {
+		s.skip = true
+		return s, nil
+	}
+	if tag == "" {
+		return s, nil
+	}
+
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		key, val, hasVal := strings.Cut(opt, "=")
+
+		switch {
+		case opt == "be":
+			s.little = false
+		case opt == "le":
+			s.little = true
+		case opt == "bool":
+			s.kind, s.size = 'b', 1
+		case hasVal && key == "len":
+			if val == "" {
+				return s, fmt.Errorf("%w: empty len reference", ErrInvalidTag)
+			}
+			s.lenField = val
+		case hasVal && key == "bits":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > 64 {
+				return s, fmt.Errorf("%w: bits=%s", ErrInvalidTag, val)
+			}
+			s.bits = n
+		case len(opt) > 1 && (opt[0] == 'u' || opt[0] == 'i' || opt[0] == 'f'):
+			n, err := strconv.Atoi(opt[1:])
+			if err != nil || (n != 8 && n != 16 && n != 32 && n != 64) || (opt[0] == 'f' && n < 32) {
+				return s, fmt.Errorf("%w: unknown type %q", ErrInvalidTag, opt)
+			}
+			s.kind, s.size = opt[0], n/8
+		default:
+			return s, fmt.Errorf("%w: unknown option %q", ErrInvalidTag, opt)
+		}
+	}
+
+	if s.bits > 0 && s.kind == 'f' {
+		return s, fmt.Errorf("%w: bits cannot be used with floats", ErrInvalidTag)
+	}
+	return s, nil
+}
+
+// width returns the encoded width in bits of a scalar of kind k.
+func (s fieldSpec) width(k reflect.Kind) (int, error) {
+	isFloat := k == reflect.Float32 || k == reflect.Float64
+	switch {
+	case s.kind == 'f' && !isFloat, (s.kind == 'u' || s.kind == 'i') && !isInteger(k), s.kind == 'b' && k != reflect.Bool:
+		return 0, fmt.Errorf("%w: wire type does not match %s", ErrInvalidTag, k)
+	case s.bits > 0 && isFloat:
+		return 0, fmt.Errorf("%w: bits cannot be used with floats", ErrInvalidTag)
+	case s.bits > 0:
+		return s.bits, nil
+	}
+	if s.size > 0 {
+		return s.size * 8, nil
+	}
+	switch k {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return 8, nil
+	case reflect.Int16, reflect.Uint16:
+		return 16, nil
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32, nil
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		return 64, nil
+	}
+	return 0, fmt.Errorf("%w: %s needs an explicit size tag", ErrUnsupportedType, k)
+}
+
+// signed reports whether an integer of kind k is two's complement on the
+// wire: the u and i wire types decide, and the Go type when there is none.
+func (s fieldSpec) signed(k reflect.Kind) bool {
+	if s.kind == 'u' || s.kind == 'i' {
+		return s.kind == 'i'
+	}
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func swapBytes(v uint64, size int) uint64 {
+	var r uint64
+	for range size {
+		r = r<<8 | v&0xff
+		v >>= 8
+	}
+	return r
+}
+
+// Marshal encodes a struct into its binary representation as described by
+// the `bin` tags of its fields.
+//
+// Fields are encoded in declaration order. Each tag is a comma separated list
+// of options:
+//   - u8, u16, u32, u64, i8, i16, i32, i64, f32, f64, bool: the wire type.
+//     When omitted it is inferred from the Go type; int and uint need one.
+//     A u or i wire type sets the signedness whatever the Go integer type,
+//     so a bin:"u16" int field holds [0, 65535].
+//   - be, le: byte order of multi-byte fields, big-endian by default.
+//   - bits=N: pack the field into N bits, MSB first. Consecutive bit fields
+//     share bytes; any other field starts on the next byte boundary.
+//   - len=Field: the slice or string length is stored in the earlier
+//     integer field named Field. Marshal writes len(slice) into that field.
+//   - "-": skip the field.
+//
+// Nested structs, fixed arrays and slices are supported. Options on an array,
+// slice or string apply to each of its elements. Unexported fields are ignored.
+//
+// Parameters:
+//   - v: A struct or a pointer to a struct.
+//
+// Returns:
+//   - The encoded bytes, zero padded to a whole byte.
+//   - An error if a tag is invalid, a type is unsupported or a value does not
+//     fit in its declared width.
+func Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil pointer", ErrUnsupportedType)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: Marshal expects a struct, got %s", ErrUnsupportedType, rv.Kind())
+	}
+
+	w := NewBitWriter(MSBFirst)
+	if err := encodeStruct(w, rv, rv.Type().Name()); err != nil {
+		return nil, err
+	}
+	w.Align()
+	return w.Bytes(), nil
+}
+
+// Unmarshal decodes data into the struct pointed to by v, following the same
+// `bin` tag rules as Marshal.
+//
+// Returns:
+//   - ErrBitUnderflow if data is shorter than the layout requires.
+//   - An error if v is not a non-nil pointer to a struct or a tag is invalid.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: Unmarshal expects a non-nil pointer", ErrUnsupportedType)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: Unmarshal expects a pointer to a struct, got %s", ErrUnsupportedType, rv.Kind())
+	}
+
+	r := NewBitReader(data, MSBFirst)
+	return decodeStruct(r, rv, rv.Type().Name())
+}
+
+// structField is a field that takes part in the binary layout.
+type structField struct {
+	index int
+	name  string
+	spec  fieldSpec
+}
+
+func structFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		spec, err := parseTag(f.Tag.Get("bin"))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if spec.skip {
+			continue
+		}
+		fields = append(fields, structField{index: i, name: f.Name, spec: spec})
+	}
+
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if ref := f.spec.lenField; ref != "" {
+			if !seen[ref] {
+				return nil, fmt.Errorf("field %s: %w: len=%s must name an earlier field", f.name, ErrInvalidTag, ref)
+			}
+			switch t.Field(f.index).Type.Kind() {
+			case reflect.Slice, reflect.String:
+			default:
+				return nil, fmt.Errorf("field %s: %w: len can only be used on slices and strings", f.name, ErrInvalidTag)
+			}
+		}
+		seen[f.name] = true
+	}
+	return fields, nil
+}
+
+func isInteger(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Int64) || (k >= reflect.Uint && k <= reflect.Uintptr)
+}
+
+func encodeStruct(w *BitWriter, v reflect.Value, path string) error {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return fmt.Errorf("bytes: %s: %w", path, err)
+	}
+
+	// lengths of slices that store their size in a sibling field
+	lengths := map[string]int{}
+	for _, f := range fields {
+		if ref := f.spec.lenField; ref != "" {
+			n := v.Field(f.index).Len()
+			if prev, ok := lengths[ref]; ok && prev != n {
+				return fmt.Errorf("bytes: %s.%s: length %d conflicts with %d for %s", path, f.name, n, prev, ref)
+			}
+			lengths[ref] = n
+		}
+	}
+
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		if n, ok := lengths[f.name]; ok {
+			if !isInteger(fv.Kind()) {
+				return fmt.Errorf("bytes: %s.%s: %w: length field must be an integer", path, f.name, ErrInvalidTag)
+			}
+			fv = reflect.New(fv.Type()).Elem()
+			if fv.CanInt() {
+				fv.SetInt(int64(n))
+			} else {
+				fv.SetUint(uint64(n))
+			}
+		}
+		if err := encodeValue(w, fv, f.spec, path+"."+f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeValue(w *BitWriter, v reflect.Value, spec fieldSpec, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		w.Align()
+		return encodeStruct(w, v, path)
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice && spec.lenField == "" {
+			return fmt.Errorf("bytes: %s: %w: slices need a len tag", path, ErrInvalidTag)
+		}
+		elem := spec
+		elem.lenField = ""
+		for i := range v.Len() {
+			if err := encodeValue(w, v.Index(i), elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		if spec.lenField == "" {
+			return fmt.Errorf("bytes: %s: %w: strings need a len tag", path, ErrInvalidTag)
+		}
+		w.Align()
+		for _, b := range []byte(v.String()) {
+			w.WriteBits(uint64(b), 8)
+		}
+		return nil
+	}
+
+	n, err := spec.width(v.Kind())
+	if err != nil {
+		return fmt.Errorf("bytes: %s: %w", path, err)
+	}
+
+	var bits uint64
+	switch {
+	case v.Kind() == reflect.Bool:
+		if v.Bool() {
+			bits = 1
+		}
+	case v.CanInt():
+		// [-2^(n-1), 2^(n-1)) for signed wire types, [0, 2^n) otherwise
+		x := v.Int()
+		fits := x >= 0 && uint64(x) <= lowMask(n)
+		if spec.signed(v.Kind()) {
+			fits = n == 64 || (x >= -(1<<(n-1)) && x < 1<<(n-1))
+		}
+		if !fits {
+			return fmt.Errorf("bytes: %s: %w: %d does not fit in %d bits", path, ErrValueRange, x, n)
+		}
+		bits = uint64(x) & lowMask(n)
+	case v.CanUint():
+		x := v.Uint()
+		limit := lowMask(n)
+		if spec.signed(v.Kind()) {
+			limit = lowMask(n - 1)
+		}
+		if x > limit {
+			return fmt.Errorf("bytes: %s: %w: %d does not fit in %d bits", path, ErrValueRange, x, n)
+		}
+		bits = x
+	case v.CanFloat():
+		if n == 32 {
+			bits = uint64(math.Float32bits(float32(v.Float())))
+		} else {
+			bits = math.Float64bits(v.Float())
+		}
+	default:
+		return fmt.Errorf("bytes: %s: %w: %s", path, ErrUnsupportedType, v.Type())
+	}
+
+	if spec.bits == 0 {
+		w.Align()
+		if spec.little {
+			bits = swapBytes(bits, n/8)
+		}
+	}
+	return w.WriteBits(bits, n)
+}
+
+func decodeStruct(r *BitReader, v reflect.Value, path string) error {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return fmt.Errorf("bytes: %s: %w", path, err)
+	}
+
+	for _, f := range fields {
+		fv := v.Field(f.index)
+		if ref := f.spec.lenField; ref != "" {
+			lv := v.FieldByName(ref)
+			var n int
+			switch {
+			case lv.CanInt():
+				n = int(lv.Int())
+			case lv.CanUint():
+				n = int(lv.Uint())
+			default:
+				return fmt.Errorf("bytes: %s.%s: %w: length field must be an integer", path, f.name, ErrInvalidTag)
+			}
+			if n < 0 || n > r.Remaining() {
+				return fmt.Errorf("bytes: %s.%s: %w: length %d exceeds input", path, f.name, ErrBitUnderflow, n)
+			}
+			if fv.Kind() == reflect.Slice {
+				fv.Set(reflect.MakeSlice(fv.Type(), n, n))
+			} else {
+				r.Align()
+				b := make([]byte, n)
+				for i := range b {
+					x, err := r.ReadBits(8)
+					if err != nil {
+						return fmt.Errorf("bytes: %s.%s: %w", path, f.name, err)
+					}
+					b[i] = byte(x)
+				}
+				fv.SetString(string(b))
+				continue
+			}
+		}
+		if err := decodeValue(r, fv, f.spec, path+"."+f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeValue(r *BitReader, v reflect.Value, spec fieldSpec, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		r.Align()
+		return decodeStruct(r, v, path)
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice && spec.lenField == "" {
+			return fmt.Errorf("bytes: %s: %w: slices need a len tag", path, ErrInvalidTag)
+		}
+		elem := spec
+		elem.lenField = ""
+		for i := range v.Len() {
+			if err := decodeValue(r, v.Index(i), elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		return fmt.Errorf("bytes: %s: %w: strings need a len tag", path, ErrInvalidTag)
+	}
+
+	n, err := spec.width(v.Kind())
+	if err != nil {
+		return fmt.Errorf("bytes: %s: %w", path, err)
+	}
+	if spec.bits == 0 {
+		r.Align()
+	}
+	bits, err := r.ReadBits(n)
+	if err != nil {
+		return fmt.Errorf("bytes: %s: %w", path, err)
+	}
+	if spec.bits == 0 && spec.little {
+		bits = swapBytes(bits, n/8)
+	}
+
+	// sign extend signed wire types, zero extend the others
+	signed := spec.signed(v.Kind())
+	x := int64(bits)
+	if signed {
+		x = int64(bits<<(64-n)) >> (64 - n)
+	}
+
+	switch {
+	case v.Kind() == reflect.Bool:
+		v.SetBool(bits != 0)
+	case v.CanInt():
+		if !signed && x < 0 {
+			return fmt.Errorf("bytes: %s: %w: %d overflows %s", path, ErrValueRange, bits, v.Type())
+		}
+		if v.OverflowInt(x) {
+			return fmt.Errorf("bytes: %s: %w: %d overflows %s", path, ErrValueRange, x, v.Type())
+		}
+		v.SetInt(x)
+	case v.CanUint():
+		if signed && x < 0 {
+			return fmt.Errorf("bytes: %s: %w: %d overflows %s", path, ErrValueRange, x, v.Type())
+		}
+		if v.OverflowUint(bits) {
+			return fmt.Errorf("bytes: %s: %w: %d overflows %s", path, ErrValueRange, bits, v.Type())
+		}
+		v.SetUint(bits)
+	case v.CanFloat():
+		if n == 32 {
+			v.SetFloat(float64(math.Float32frombits(uint32(bits))))
+		} else {
+			v.SetFloat(math.Float64frombits(bits))
+		}
+	default:
+		return fmt.Errorf("bytes: %s: %w: %s", path, ErrUnsupportedType, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package bytes
+
+import (
+	stdbytes "bytes"
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type header struct {
+	Version uint8 `bin:"bits=3"`
+	Flags   uint8 `bin:"bits=5"`
+	Kind    uint16
+	Seq     uint32 `bin:"le"`
+}
+
+type packet struct {
+	Header  header
+	Count   uint8
+	Samples []int16 `bin:"len=Count"`
+	Trailer [2]byte
+	Temp    float32
+	Offset  int8   `bin:"bits=4"`
+	Ready   bool   `bin:"bits=1"`
+	Skipped int    `bin:"-"`
+	NameLen int    `bin:"u8"`
+	Name    string `bin:"len=NameLen"`
+	private int
+}
+
+func TestMarshalLayout(t *testing.T) {
+	p := packet{
+		Header:  header{Version: 5, Flags: 1, Kind: 0x0102, Seq: 0x03040506},
+		Samples: []int16{-1, 2},
+		Trailer: [2]byte{0xaa, 0xbb},
+		Temp:    1.5,
+		Offset:  -2,
+		Ready:   true,
+		Skipped: 42,
+		Name:    "ok",
+	}
+
+	expected := []byte{
+		0xa1,       // version 101, flags 00001
+		0x01, 0x02, // kind, big-endian
+		0x06, 0x05, 0x04, 0x03, // seq, little-endian
+		0x02,                   // count, filled from len(Samples)
+		0xff, 0xff, 0x00, 0x02, // samples
+		0xaa, 0xbb, // trailer
+		0x3f, 0xc0, 0x00, 0x00, // 1.5 as float32
+		0xe8, // offset 1110, ready 1, padding 000
+		0x02, 'o', 'k',
+	}
+
+	got, err := Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stdbytes.Equal(got, expected) {
+		t.Errorf("incorrect encoding\nexpected = % x\ngot      = % x", expected, got)
+	}
+
+	var decoded packet
+	if err := Unmarshal(got, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Count, p.NameLen, p.Skipped = 2, 2, 0
+	if decoded.Header != p.Header || decoded.Count != p.Count || decoded.Trailer != p.Trailer ||
+		decoded.Temp != p.Temp || decoded.Offset != p.Offset || decoded.Ready != p.Ready ||
+		decoded.Name != p.Name || decoded.Skipped != 0 || len(decoded.Samples) != 2 ||
+		decoded.Samples[0] != -1 || decoded.Samples[1] != 2 {
+		t.Errorf("incorrect round trip\nexpected = %+v\ngot      = %+v", p, decoded)
+	}
+}
+
+// intRange returns the bounds of an n-bit integer.
+func intRange(signed bool, n int) (lo, hi *big.Int) {
+	if !signed {
+		return big.NewInt(0), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), uint(n-1))
+	return new(big.Int).Neg(half), half.Sub(half, big.NewInt(1))
+}
+
+func TestMarshalWireSignedness(t *testing.T) {
+	goTypes := []reflect.Type{
+		reflect.TypeFor[int](), reflect.TypeFor[int8](), reflect.TypeFor[int16](), reflect.TypeFor[int32](), reflect.TypeFor[int64](),
+		reflect.TypeFor[uint](), reflect.TypeFor[uint8](), reflect.TypeFor[uint16](), reflect.TypeFor[uint32](), reflect.TypeFor[uint64](),
+	}
+	inRange := func(x, lo, hi *big.Int) bool { return x.Cmp(lo) >= 0 && x.Cmp(hi) <= 0 }
+	one := big.NewInt(1)
+
+	for _, wire := range []string{"u8", "i8", "u16", "i16", "u32", "i32", "u64", "i64"} {
+		n, _ := strconv.Atoi(wire[1:])
+		wireLo, wireHi := intRange(wire[0] == 'i', n)
+		// encoded returns the big-endian wire bytes of x
+		encoded := func(x *big.Int) []byte {
+			m := new(big.Int).Lsh(one, uint(n))
+			return new(big.Int).Mod(x, m).FillBytes(make([]byte, n/8))
+		}
+
+		for _, typ := range goTypes {
+			goLo, goHi := intRange(typ.Kind() <= reflect.Int64, typ.Bits())
+			st := reflect.StructOf([]reflect.StructField{{Name: "V", Type: typ, Tag: reflect.StructTag(`bin:"` + wire + `"`)}})
+			name := wire + " as " + typ.String()
+			set := func(v reflect.Value, x *big.Int) {
+				if v.CanInt() {
+					v.SetInt(x.Int64())
+				} else {
+					v.SetUint(x.Uint64())
+				}
+			}
+			get := func(v reflect.Value) *big.Int {
+				if v.CanInt() {
+					return big.NewInt(v.Int())
+				}
+				return new(big.Int).SetUint64(v.Uint())
+			}
+
+			// the bounds shared by the wire and Go types round trip
+			lo, hi := wireLo, wireHi
+			if goLo.Cmp(lo) > 0 {
+				lo = goLo
+			}
+			if goHi.Cmp(hi) < 0 {
+				hi = goHi
+			}
+			for _, x := range []*big.Int{lo, hi} {
+				in := reflect.New(st)
+				set(in.Elem().Field(0), x)
+				data, err := Marshal(in.Interface())
+				if err != nil {
+					t.Errorf("%s: Marshal(%v): %v", name, x, err)
+					continue
+				}
+				if !stdbytes.Equal(data, encoded(x)) {
+					t.Errorf("%s: Marshal(%v) = % x, want % x", name, x, data, encoded(x))
+				}
+				out := reflect.New(st)
+				if err := Unmarshal(data, out.Interface()); err != nil {
+					t.Errorf("%s: Unmarshal(% x): %v", name, data, err)
+				} else if got := get(out.Elem().Field(0)); got.Cmp(x) != 0 {
+					t.Errorf("%s: round trip of %v gave %v", name, x, got)
+				}
+			}
+
+			// Go values outside the wire range are rejected
+			for _, x := range []*big.Int{new(big.Int).Sub(wireLo, one), new(big.Int).Add(wireHi, one)} {
+				if !inRange(x, goLo, goHi) {
+					continue
+				}
+				in := reflect.New(st)
+				set(in.Elem().Field(0), x)
+				if _, err := Marshal(in.Interface()); !errors.Is(err, ErrValueRange) {
+					t.Errorf("%s: Marshal(%v): expected ErrValueRange, got %v", name, x, err)
+				}
+			}
+
+			// and so are wire values outside the Go range
+			for _, x := range []*big.Int{wireLo, wireHi} {
+				if inRange(x, goLo, goHi) {
+					continue
+				}
+				if err := Unmarshal(encoded(x), reflect.New(st).Interface()); !errors.Is(err, ErrValueRange) {
+					t.Errorf("%s: Unmarshal(%v): expected ErrValueRange, got %v", name, x, err)
+				}
+			}
+		}
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type tooWide struct {
+		V uint8 `bin:"bits=3"`
+	}
+	if _, err := Marshal(tooWide{V: 8}); !errors.Is(err, ErrValueRange) {
+		t.Errorf("expected ErrValueRange, got %v", err)
+	}
+
+	type laterLen struct {
+		Data []byte `bin:"len=N"`
+		N    uint8
+	}
+	if _, err := Marshal(laterLen{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag for forward len reference, got %v", err)
+	}
+
+	type noLen struct {
+		Data []byte
+	}
+	if _, err := Marshal(noLen{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag for slice without len, got %v", err)
+	}
+
+	type badTag struct {
+		V uint8 `bin:"u12"`
+	}
+	if _, err := Marshal(badTag{}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+
+	type plainInt struct {
+		V int
+	}
+	if _, err := Marshal(plainInt{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for int without size, got %v", err)
+	}
+
+	if _, err := Marshal(42); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for non-struct, got %v", err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var h header
+	if err := Unmarshal([]byte{0xa1, 0x01}, &h); !errors.Is(err, ErrBitUnderflow) {
+		t.Errorf("expected ErrBitUnderflow on short input, got %v", err)
+	}
+	if err := Unmarshal([]byte{0xa1}, h); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for non-pointer, got %v", err)
+	}
+
+	type frame struct {
+		N    uint8
+		Data []uint32 `bin:"len=N"`
+	}
+	var f frame
+	if err := Unmarshal([]byte{0xff, 0x00}, &f); !errors.Is(err, ErrBitUnderflow) {
+		t.Errorf("expected ErrBitUnderflow for oversized length, got %v", err)
+	}
+}
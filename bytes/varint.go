@@ -0,0 +1,177 @@
+package bytes
+
+import (
+	"errors"
+	"io"
+)
+
+// MaxVarintLen64 is the maximum number of bytes a 64-bit value can take
+// once LEB128 encoded.
+const MaxVarintLen64 = 10
+
+var (
+	// ErrOverflow is returned when a varint does not fit in 64 bits.
+	ErrOverflow = errors.New("bytes: varint overflows a 64-bit integer")
+	// ErrTruncated is returned when the input ends in the middle of a varint.
+	ErrTruncated = errors.New("bytes: truncated varint")
+)
+
+// ZigZagEncode maps a signed integer to an unsigned one so that values of
+// small magnitude (positive or negative) produce small varints:
+// 0 -> 0, -1 -> 1, 1 -> 2, -2 -> 3, ...
+func ZigZagEncode(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
+
+// ZigZagDecode reverses ZigZagEncode.
+func ZigZagDecode(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// AppendUvarint appends the LEB128 encoding of x to buf and returns the
+// extended slice.
+//
+// Parameters:
+//   - buf: The slice to append to, may be nil.
+//   - x: The value to encode.
+//
+// Returns:
+//   - The extended slice.
+func AppendUvarint(buf []byte, x uint64) []byte {
+	for x >= 0x80 {
+		buf = append(buf, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(buf, byte(x))
+}
+
+// AppendVarint appends the zigzag + LEB128 encoding of x to buf and returns
+// the extended slice.
+func AppendVarint(buf []byte, x int64) []byte {
+	return AppendUvarint(buf, ZigZagEncode(x))
+}
+
+// PutUvarint encodes x into buf and returns the number of bytes written.
+// It panics if buf is too small; a buffer of MaxVarintLen64 bytes is
+// always large enough.
+func PutUvarint(buf []byte, x uint64) int {
+	i := 0
+	for x >= 0x80 {
+		buf[i] = byte(x) | 0x80
+		x >>= 7
+		i++
+	}
+	buf[i] = byte(x)
+	return i + 1
+}
+
+// PutVarint encodes x using zigzag + LEB128 into buf and returns the number
+// of bytes written. It panics if buf is too small.
+func PutVarint(buf []byte, x int64) int {
+	return PutUvarint(buf, ZigZagEncode(x))
+}
+
+// UvarintLen returns the number of bytes needed to encode x.
+func UvarintLen(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
+
+// Uvarint decodes a LEB128 value from the start of buf.
+//
+// Parameters:
+//   - buf: The encoded bytes.
+//
+// Returns:
+//   - The decoded value.
+//   - The number of bytes consumed.
+//   - ErrTruncated if buf ends before the varint does, or ErrOverflow if
+//     the encoded value does not fit in 64 bits.
+func Uvarint(buf []byte) (uint64, int, error) {
+	var x uint64
+	var s uint
+	for i, b := range buf {
+		if i == MaxVarintLen64 {
+			return 0, 0, ErrOverflow
+		}
+		if b < 0x80 {
+			if i == MaxVarintLen64-1 && b > 1 {
+				return 0, 0, ErrOverflow
+			}
+			return x | uint64(b)<<s, i + 1, nil
+		}
+		x |= uint64(b&0x7f) << s
+		s += 7
+	}
+	return 0, 0, ErrTruncated
+}
+
+// Varint decodes a zigzag + LEB128 value from the start of buf.
+// It follows the same conventions as Uvarint.
+func Varint(buf []byte) (int64, int, error) {
+	u, n, err := Uvarint(buf)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ZigZagDecode(u), n, nil
+}
+
+// WriteUvarint writes the LEB128 encoding of x to w.
+//
+// Returns:
+//   - The number of bytes written.
+//   - Any error returned by w.
+func WriteUvarint(w io.Writer, x uint64) (int, error) {
+	var buf [MaxVarintLen64]byte
+	n := PutUvarint(buf[:], x)
+	return w.Write(buf[:n])
+}
+
+// WriteVarint writes the zigzag + LEB128 encoding of x to w.
+func WriteVarint(w io.Writer, x int64) (int, error) {
+	return WriteUvarint(w, ZigZagEncode(x))
+}
+
+// ReadUvarint reads a LEB128 value from r one byte at a time.
+//
+// Returns:
+//   - The decoded value.
+//   - io.EOF if r is exhausted before any byte is read, ErrTruncated if it
+//     ends in the middle of the varint, ErrOverflow if the value does not
+//     fit in 64 bits, or any other error returned by r.
+func ReadUvarint(r io.ByteReader) (uint64, error) {
+	var x uint64
+	var s uint
+	for i := range MaxVarintLen64 {
+		b, err := r.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				return 0, ErrTruncated
+			}
+			return 0, err
+		}
+		if b < 0x80 {
+			if i == MaxVarintLen64-1 && b > 1 {
+				return 0, ErrOverflow
+			}
+			return x | uint64(b)<<s, nil
+		}
+		x |= uint64(b&0x7f) << s
+		s += 7
+	}
+	return 0, ErrOverflow
+}
+
+// ReadVarint reads a zigzag + LEB128 value from r.
+// It follows the same conventions as ReadUvarint.
+func ReadVarint(r io.ByteReader) (int64, error) {
+	u, err := ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	return ZigZagDecode(u), nil
+}
@@ -0,0 +1,164 @@
+package bytes
+
+import (
+	stdbytes "bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestUvarintRoundTrip(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 300, 16383, 16384, 1 << 32, math.MaxUint64}
+
+	for _, v := range values {
+		buf := AppendUvarint(nil, v)
+		if len(buf) != UvarintLen(v) {
+			t.Errorf("incorrect encoded length for %d, expected = %d, got = %d", v, UvarintLen(v), len(buf))
+		}
+
+		got, n, err := Uvarint(buf)
+		if err != nil {
+			t.Fatalf("unexpected error decoding %d: %v", v, err)
+		}
+		if got != v || n != len(buf) {
+			t.Errorf("incorrect value, expected = %d (%d bytes), got = %d (%d bytes)", v, len(buf), got, n)
+		}
+	}
+}
+
+func TestVarintKnownEncodings(t *testing.T) {
+	tests := []struct {
+		value    int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{-1, []byte{0x01}},
+		{1, []byte{0x02}},
+		{-2, []byte{0x03}},
+		{63, []byte{0x7e}},
+		{-64, []byte{0x7f}},
+		{64, []byte{0x80, 0x01}},
+		{150, []byte{0xac, 0x02}},
+	}
+
+	for _, tt := range tests {
+		buf := AppendVarint(nil, tt.value)
+		if !stdbytes.Equal(buf, tt.expected) {
+			t.Errorf("incorrect encoding of %d, expected = %x, got = %x", tt.value, tt.expected, buf)
+		}
+		got, _, err := Varint(buf)
+		if err != nil || got != tt.value {
+			t.Errorf("incorrect decoding of %x, expected = %d, got = %d (%v)", buf, tt.value, got, err)
+		}
+	}
+}
+
+func TestZigZagExtremes(t *testing.T) {
+	for _, v := range []int64{math.MinInt64, math.MaxInt64, 0, -1} {
+		if got := ZigZagDecode(ZigZagEncode(v)); got != v {
+			t.Errorf("zigzag round trip failed, expected = %d, got = %d", v, got)
+		}
+	}
+}
+
+func TestUvarintMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+		err  error
+	}{
+		{"empty", nil, ErrTruncated},
+		{"truncated", []byte{0x80, 0x80}, ErrTruncated},
+		{"tenth byte too large", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}, ErrOverflow},
+		{"eleven bytes", []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := Uvarint(tt.buf); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+		}
+		if _, err := ReadUvarint(stdbytes.NewReader(tt.buf)); tt.buf != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected stream error %v, got %v", tt.name, tt.err, err)
+		}
+	}
+
+	if _, err := ReadUvarint(stdbytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("expected io.EOF on empty stream, got %v", err)
+	}
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	values := []int64{0, -1, 1, 1000, -1000, math.MaxInt64, math.MinInt64}
+
+	var buf stdbytes.Buffer
+	for _, v := range values {
+		if _, err := WriteVarint(&buf, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, v := range values {
+		got, err := ReadVarint(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v {
+			t.Errorf("incorrect value, expected = %d, got = %d", v, got)
+		}
+	}
+
+	if _, err := ReadVarint(&buf); err != io.EOF {
+		t.Errorf("expected io.EOF after last value, got %v", err)
+	}
+}
+
+func FuzzUvarint(f *testing.F) {
+	f.Add([]byte{0x00})
+	f.Add([]byte{0xac, 0x02})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02})
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		want, wantN := binary.Uvarint(buf)
+		got, n, err := Uvarint(buf)
+
+		switch {
+		case wantN > 0:
+			if err != nil || got != want || n != wantN {
+				t.Fatalf("Uvarint(%x) = %d, %d, %v; binary.Uvarint = %d, %d", buf, got, n, err, want, wantN)
+			}
+		case wantN == 0:
+			if !errors.Is(err, ErrTruncated) {
+				t.Fatalf("Uvarint(%x): expected ErrTruncated, got %v", buf, err)
+			}
+		default:
+			if !errors.Is(err, ErrOverflow) {
+				t.Fatalf("Uvarint(%x): expected ErrOverflow, got %v", buf, err)
+			}
+		}
+
+		streamed, err := ReadUvarint(stdbytes.NewReader(buf))
+		if wantN > 0 && (err != nil || streamed != want) {
+			t.Fatalf("ReadUvarint(%x) = %d, %v; binary.Uvarint = %d", buf, streamed, err, want)
+		}
+	})
+}
+
+func FuzzVarintRoundTrip(f *testing.F) {
+	f.Add(int64(0))
+	f.Add(int64(-1))
+	f.Add(int64(math.MinInt64))
+
+	f.Fuzz(func(t *testing.T, v int64) {
+		buf := AppendVarint(nil, v)
+		if want := binary.AppendVarint(nil, v); !stdbytes.Equal(buf, want) {
+			t.Fatalf("AppendVarint(%d) = %x, binary.AppendVarint = %x", v, buf, want)
+		}
+		got, n, err := Varint(buf)
+		if err != nil || got != v || n != len(buf) {
+			t.Fatalf("Varint(%x) = %d, %d, %v; expected %d", buf, got, n, err, v)
+		}
+	})
+}
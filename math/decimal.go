@@ -0,0 +1,520 @@
+package math
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidDecimal is returned when a string cannot be parsed as a Decimal.
+	ErrInvalidDecimal = errors.New("math: invalid decimal")
+	// ErrDivisionByZero is returned when dividing by a zero Decimal.
+	ErrDivisionByZero = errors.New("math: division by zero")
+)
+
+// Decimal is an arbitrary-precision fixed-point number: an integer
+// coefficient scaled by a power of ten. 12.30 is stored as 1230 with scale 2,
+// so values such as monetary amounts are represented exactly.
+//
+// Decimal values are immutable; every operation returns a new value. The zero
+// value is 0 with scale 0.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+var bigTen = big.NewInt(10)
+
+// maxParseScale bounds the exponent and the scale accepted by ParseDecimal.
+// Parsing "1e200000000" would otherwise compute a power of ten with two
+// hundred million digits, so untrusted input could hang the process.
+const maxParseScale = 10000
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// NewDecimal creates a Decimal equal to unscaled × 10^-scale.
+// A negative scale multiplies the coefficient, so the result always has a
+// scale of zero or more.
+//
+// Examples:
+//
+//	NewDecimal(1230, 2)  // 12.30
+//	NewDecimal(5, -2)    // 500
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	c := big.NewInt(unscaled)
+	if scale < 0 {
+		c.Mul(c, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{coef: c, scale: scale}
+}
+
+// DecimalFromInt creates a Decimal equal to n with scale 0.
+func DecimalFromInt(n int64) Decimal {
+	return NewDecimal(n, 0)
+}
+
+// DecimalFromFloat converts f to a Decimal with the given scale.
+//
+// Like RoundWithMode, it starts from the shortest decimal representation of
+// f, so DecimalFromFloat(0.1, 2, HalfUp) is exactly 0.10 and
+// DecimalFromFloat(2.675, 2, HalfUp) is 2.68.
+//
+// Returns:
+//   - An error if f is NaN or infinite.
+func DecimalFromFloat(f float64, scale int32, mode RoundingMode) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%w: %v", ErrInvalidDecimal, f)
+	}
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'e', -1, 64))
+	if err != nil {
+		return Decimal{}, err
+	}
+	return d.Round(scale, mode), nil
+}
+
+// ParseDecimal parses a decimal string such as "-1234.50" or "1.5e-3".
+// The scale of the result is the number of fractional digits, so trailing
+// zeros are preserved.
+//
+// The exponent, and the scale it gives, must be within ±10000, which keeps
+// untrusted input such as JSON or database values from requesting huge
+// powers of ten.
+//
+// Returns:
+//   - ErrInvalidDecimal if s is not a valid decimal number or is out of range.
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	invalid := func() (Decimal, error) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+	}
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || e < -maxParseScale || e > maxParseScale {
+			return invalid()
+		}
+		exp, s = e, s[:i]
+	}
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg, s = s[0] == '-', s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" {
+		return invalid()
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return invalid()
+		}
+	}
+
+	c, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		c.Neg(c)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale < -maxParseScale || scale > maxParseScale {
+		return invalid()
+	}
+	if scale < 0 {
+		c.Mul(c, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: c, scale: int32(scale)}, nil
+}
+
+// ParseDecimalGrouped parses a decimal string that uses the given thousands
+// separator and decimal point, such as "1,234,567.89" or "1.234.567,89".
+// Separators must split the integer part into groups of three digits.
+//
+// Returns:
+//   - ErrInvalidDecimal if s is not a valid grouped decimal number.
+func ParseDecimalGrouped(s string, thousands, point rune) (Decimal, error) {
+	intPart, fracPart, hasPoint := strings.Cut(s, string(point))
+
+	sign := ""
+	if intPart != "" && (intPart[0] == '-' || intPart[0] == '+') {
+		sign, intPart = intPart[:1], intPart[1:]
+	}
+
+	groups := strings.Split(intPart, string(thousands))
+	for i, g := range groups {
+		if (i > 0 && len(g) != 3) || (i == 0 && len(groups) > 1 && (len(g) == 0 || len(g) > 3)) {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+		}
+	}
+
+	plain := sign + strings.Join(groups, "")
+	if hasPoint {
+		plain += "." + fracPart
+	}
+	if strings.ContainsAny(plain, "eE") {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	d, err := ParseDecimal(plain)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	return d, nil
+}
+
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescaled returns the coefficient of d expressed at a larger scale.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	c := new(big.Int).Set(d.int())
+	if scale > d.scale {
+		c.Mul(c, pow10(scale-d.scale))
+	}
+	return c
+}
+
+// Scale returns the number of fractional digits of d.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Coefficient returns the unscaled integer value of d.
+func (d Decimal) Coefficient() *big.Int {
+	return new(big.Int).Set(d.int())
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Add returns d + o. The result has the larger of both scales.
+func (d Decimal) Add(o Decimal) Decimal {
+	s := max(d.scale, o.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescaled(s), o.rescaled(s)), scale: s}
+}
+
+// Sub returns d - o. The result has the larger of both scales.
+func (d Decimal) Sub(o Decimal) Decimal {
+	s := max(d.scale, o.scale)
+	return Decimal{coef: new(big.Int).Sub(d.rescaled(s), o.rescaled(s)), scale: s}
+}
+
+// Mul returns d × o exactly. The result scale is the sum of both scales.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Div returns d / o rounded to scale fractional digits using mode. As with
+// Round, a negative scale rounds to tens, hundreds and so on.
+//
+// Returns:
+//   - ErrDivisionByZero if o is zero.
+func (d Decimal) Div(o Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	// a negative scale divides by 10^-scale as well, rounds once to an
+	// integer and multiplies back, so the digits are not rounded twice
+	var tens int32
+	if scale < 0 {
+		tens, scale = -scale, 0
+	}
+
+	// d/o = (dc × 10^-ds) / (oc × 10^-os); scaling by 10^scale gives
+	// dc × 10^(scale+os-ds) / oc
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(o.int())
+	if shift := scale + o.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	if tens == 0 {
+		return Decimal{coef: divRound(num, den, mode), scale: scale}, nil
+	}
+	den.Mul(den, pow10(tens))
+	q := divRound(num, den, mode)
+	return Decimal{coef: q.Mul(q, pow10(tens)), scale: 0}, nil
+}
+
+// divRound returns num / den rounded to an integer using mode.
+func divRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	neg := (num.Sign() < 0) != (den.Sign() < 0)
+	// compare twice the remainder with the divisor to locate the tie
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case HalfUp:
+		away = cmp >= 0
+	case HalfDown:
+		away = cmp > 0
+	case HalfEven:
+		away = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+	case Ceiling:
+		away = !neg
+	case Floor:
+		away = neg
+	case Truncate:
+		away = false
+	}
+
+	if away {
+		if neg {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Round returns d rounded to scale fractional digits using mode. A larger
+// scale pads d with zeros; a negative scale rounds to tens, hundreds and so
+// on, with a result scale of zero.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale >= d.scale {
+		return Decimal{coef: d.rescaled(scale), scale: scale}
+	}
+	q := divRound(d.int(), pow10(d.scale-scale), mode)
+	if scale < 0 {
+		return Decimal{coef: q.Mul(q, pow10(-scale)), scale: 0}
+	}
+	return Decimal{coef: q, scale: scale}
+}
+
+// Cmp compares d and o and returns -1 if d < o, 0 if d == o and 1 if d > o.
+// Scale is ignored: 1.50 and 1.5 compare equal.
+func (d Decimal) Cmp(o Decimal) int {
+	s := max(d.scale, o.scale)
+	return d.rescaled(s).Cmp(o.rescaled(s))
+}
+
+// Equal reports whether d and o have the same numeric value.
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// LessThan reports whether d < o.
+func (d Decimal) LessThan(o Decimal) bool {
+	return d.Cmp(o) < 0
+}
+
+// GreaterThan reports whether d > o.
+func (d Decimal) GreaterThan(o Decimal) bool {
+	return d.Cmp(o) > 0
+}
+
+// Allocate splits d into parts proportional to ratios without losing any
+// unit of its last digit. Parts keep the scale of d, and the leftover units
+// go to the parts with the largest fractional shares (earlier parts first on
+// ties), so the parts always add up to d.
+//
+// Parameters:
+//   - ratios: Non-negative weights, at least one of them positive.
+//
+// Returns:
+//   - One part per ratio.
+//   - An error if ratios is empty, contains a negative weight or sums to zero.
+//
+// Example:
+//
+//	NewDecimal(10000, 2).Allocate(1, 1, 1) // 33.34, 33.33, 33.33
+func (d Decimal) Allocate(ratios ...int64) ([]Decimal, error) {
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("math: negative allocation ratio %d", r)
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("math: allocation ratios must sum to a positive value")
+	}
+
+	amount := new(big.Int).Abs(d.int())
+	parts := make([]*big.Int, len(ratios))
+	rems := make([]*big.Int, len(ratios))
+	left := new(big.Int).Set(amount)
+	for i, r := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(r))
+		parts[i], rems[i] = share.QuoRem(share, total, new(big.Int))
+		left.Sub(left, parts[i])
+	}
+
+	// hand out the leftover units by largest remainder
+	for left.Sign() > 0 {
+		best := -1
+		for i := range rems {
+			if ratios[i] > 0 && (best < 0 || rems[i].Cmp(rems[best]) > 0) {
+				best = i
+			}
+		}
+		parts[best].Add(parts[best], big.NewInt(1))
+		rems[best].SetInt64(-1)
+		left.Sub(left, big.NewInt(1))
+	}
+
+	out := make([]Decimal, len(parts))
+	for i, p := range parts {
+		if d.Sign() < 0 {
+			p.Neg(p)
+		}
+		out[i] = Decimal{coef: p, scale: d.scale}
+	}
+	return out, nil
+}
+
+// Split divides d into n parts that differ by at most one unit of its last
+// digit and add up to d. It panics if n is not positive.
+func (d Decimal) Split(n int) []Decimal {
+	if n <= 0 {
+		panic("math: Split requires a positive number of parts")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	parts, _ := d.Allocate(ratios...)
+	return parts
+}
+
+// Float64 returns the float64 nearest to d. Round d first to control how
+// excess digits are dropped.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Int64 returns the integer part of d, truncated toward zero, and whether it
+// fits in an int64.
+func (d Decimal) Int64() (int64, bool) {
+	q := d.Round(0, Truncate).int()
+	return q.Int64(), q.IsInt64()
+}
+
+// String formats d with exactly Scale fractional digits, e.g. "-1234.50".
+func (d Decimal) String() string {
+	return d.FormatGrouped(0, '.')
+}
+
+// FormatGrouped formats d with a thousands separator and decimal point,
+// e.g. FormatGrouped(',', '.') gives "1,234,567.89". A zero thousands
+// separator disables grouping.
+func (d Decimal) FormatGrouped(thousands, point rune) string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	intPart := digits[:len(digits)-int(d.scale)]
+	fracPart := digits[len(digits)-int(d.scale):]
+
+	var sb strings.Builder
+	if d.Sign() < 0 {
+		sb.WriteByte('-')
+	}
+	for i, c := range intPart {
+		if thousands != 0 && i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteRune(thousands)
+		}
+		sb.WriteRune(c)
+	}
+	if d.scale > 0 {
+		sb.WriteRune(point)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// MarshalJSON encodes d as a JSON string, e.g. "12.30", so no precision is
+// lost in decoders that read numbers as float64.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number into d.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unq, err := strconv.Unquote(s); err == nil {
+		s = unq
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements driver.Valuer, storing d as its string representation so
+// it maps onto SQL NUMERIC and DECIMAL columns without loss.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts strings, byte slices, integers and
+// floats; floats are converted from their shortest representation.
+func (d *Decimal) Scan(src any) error {
+	var (
+		v   Decimal
+		err error
+	)
+	switch s := src.(type) {
+	case string:
+		v, err = ParseDecimal(s)
+	case []byte:
+		v, err = ParseDecimal(string(s))
+	case int64:
+		v = DecimalFromInt(s)
+	case float64:
+		v, err = ParseDecimal(strconv.FormatFloat(s, 'e', -1, 64))
+	default:
+		return fmt.Errorf("math: cannot scan %T into Decimal", src)
+	}
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,303 @@
+package math
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q): %v", s, err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0", "0"},
+		{"12.30", "12.30"},
+		{"-0.05", "-0.05"},
+		{"+7", "7"},
+		{".5", "0.5"},
+		{"1.5e3", "1500"},
+		{"1.5e-3", "0.0015"},
+		{"123456789012345678901234567890.123", "123456789012345678901234567890.123"},
+	}
+
+	for _, tt := range tests {
+		if got := mustDecimal(t, tt.input).String(); got != tt.expected {
+			t.Errorf("ParseDecimal(%q): expected = %s, got = %s", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, bad := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1,000"} {
+		if _, err := ParseDecimal(bad); !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("ParseDecimal(%q): expected ErrInvalidDecimal, got %v", bad, err)
+		}
+	}
+}
+
+func TestParseDecimalRange(t *testing.T) {
+	for _, ok := range []string{"1e10000", "1e-10000", "0.1e-9999"} {
+		if _, err := ParseDecimal(ok); err != nil {
+			t.Errorf("ParseDecimal(%q): %v", ok, err)
+		}
+	}
+
+	// huge exponents must fail fast instead of computing 10^exp
+	for _, bad := range []string{"1e200000000", "1e-200000000", "1e10001", "1e-10001", "0.1e-10000", "0." + strings.Repeat("0", 10000) + "1"} {
+		if _, err := ParseDecimal(bad); !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("ParseDecimal(%.20q): expected ErrInvalidDecimal, got %v", bad, err)
+		}
+	}
+	var d Decimal
+	if err := json.Unmarshal([]byte(`1e200000000`), &d); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("UnmarshalJSON: expected ErrInvalidDecimal, got %v", err)
+	}
+	if err := d.Scan("1e200000000"); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("Scan: expected ErrInvalidDecimal, got %v", err)
+	}
+}
+
+func TestDecimalGrouped(t *testing.T) {
+	d, err := ParseDecimalGrouped("-1,234,567.89", ',', '.')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "-1234567.89" {
+		t.Errorf("incorrect value, got = %s", d)
+	}
+	if got := d.FormatGrouped('.', ','); got != "-1.234.567,89" {
+		t.Errorf("incorrect grouped format, got = %s", got)
+	}
+	if got := NewDecimal(99, 0).FormatGrouped(',', '.'); got != "99" {
+		t.Errorf("incorrect grouped format, got = %s", got)
+	}
+
+	for _, bad := range []string{"1,23,456", ",123", "1234,567"} {
+		if _, err := ParseDecimalGrouped(bad, ',', '.'); !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("ParseDecimalGrouped(%q): expected ErrInvalidDecimal, got %v", bad, err)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := mustDecimal(t, "0.1")
+	b := mustDecimal(t, "0.2")
+
+	if got := a.Add(b); !got.Equal(mustDecimal(t, "0.3")) {
+		t.Errorf("0.1 + 0.2: expected = 0.3, got = %s", got)
+	}
+	if got := a.Sub(mustDecimal(t, "1.25")).String(); got != "-1.15" {
+		t.Errorf("0.1 - 1.25: expected = -1.15, got = %s", got)
+	}
+	if got := mustDecimal(t, "19.99").Mul(mustDecimal(t, "3")).String(); got != "59.97" {
+		t.Errorf("19.99 * 3: expected = 59.97, got = %s", got)
+	}
+
+	if !mustDecimal(t, "1.50").Equal(mustDecimal(t, "1.5")) {
+		t.Errorf("1.50 and 1.5 must compare equal")
+	}
+	if !a.LessThan(b) || !b.GreaterThan(a) || a.Cmp(a) != 0 {
+		t.Errorf("incorrect comparison between %s and %s", a, b)
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		scale    int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"10", "3", 2, HalfUp, "3.33"},
+		{"20", "3", 2, HalfUp, "6.67"},
+		{"-20", "3", 2, HalfUp, "-6.67"},
+		{"1", "8", 2, HalfEven, "0.12"},
+		{"3", "8", 2, HalfEven, "0.38"},
+		{"1", "8", 2, HalfDown, "0.12"},
+		{"1", "3", 2, Ceiling, "0.34"},
+		{"-1", "3", 2, Ceiling, "-0.33"},
+		{"-1", "3", 2, Floor, "-0.34"},
+		{"2", "3", 0, Truncate, "0"},
+		{"1.000", "0.25", 1, HalfUp, "4.0"},
+		{"12345", "1", -2, HalfUp, "12300"},
+		{"1249.6", "1", -2, HalfUp, "1200"},
+		{"-1249.6", "1", -2, HalfUp, "-1200"},
+		{"2500", "2", -2, HalfEven, "1200"},
+	}
+
+	for _, tt := range tests {
+		got, err := mustDecimal(t, tt.a).Div(mustDecimal(t, tt.b), tt.scale, tt.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != tt.expected {
+			t.Errorf("%s / %s (scale %d, %v): expected = %s, got = %s", tt.a, tt.b, tt.scale, tt.mode, tt.expected, got)
+		}
+	}
+
+	if _, err := DecimalFromInt(1).Div(Decimal{}, 2, HalfUp); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("expected ErrDivisionByZero, got %v", err)
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		scale    int32
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", 2, HalfUp, "2.35"},
+		{"2.345", 2, HalfEven, "2.34"},
+		{"2.355", 2, HalfEven, "2.36"},
+		{"-2.345", 2, HalfDown, "-2.34"},
+		{"2.341", 2, Ceiling, "2.35"},
+		{"-2.349", 2, Truncate, "-2.34"},
+		{"2.5", 4, HalfUp, "2.5000"},
+		{"1250", -2, HalfEven, "1200"},
+		{"1251", -2, HalfEven, "1300"},
+	}
+
+	for _, tt := range tests {
+		if got := mustDecimal(t, tt.input).Round(tt.scale, tt.mode).String(); got != tt.expected {
+			t.Errorf("Round(%s, %d, %v): expected = %s, got = %s", tt.input, tt.scale, tt.mode, tt.expected, got)
+		}
+	}
+}
+
+func TestDecimalAllocate(t *testing.T) {
+	parts, err := NewDecimal(10000, 2).Allocate(1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"33.34", "33.33", "33.33"}
+	for i, p := range parts {
+		if p.String() != expected[i] {
+			t.Errorf("part %d: expected = %s, got = %s", i, expected[i], p)
+		}
+	}
+
+	parts, _ = mustDecimal(t, "-0.05").Allocate(70, 30, 0)
+	sum := Decimal{}
+	for _, p := range parts {
+		sum = sum.Add(p)
+	}
+	if !sum.Equal(mustDecimal(t, "-0.05")) || parts[2].Sign() != 0 {
+		t.Errorf("allocation lost cents: %v", parts)
+	}
+	if parts[0].String() != "-0.04" || parts[1].String() != "-0.01" {
+		t.Errorf("incorrect allocation, got = %v", parts)
+	}
+
+	split := mustDecimal(t, "100.00").Split(7)
+	sum = Decimal{}
+	for _, p := range split {
+		sum = sum.Add(p)
+		if d := p.Sub(split[len(split)-1]).Abs(); d.GreaterThan(mustDecimal(t, "0.01")) {
+			t.Errorf("parts differ by more than one cent: %v", split)
+		}
+	}
+	if sum.String() != "100.00" {
+		t.Errorf("split parts must add up, got = %s", sum)
+	}
+
+	if _, err := DecimalFromInt(1).Allocate(0, 0); err == nil {
+		t.Errorf("expected error for zero ratios")
+	}
+	if _, err := DecimalFromInt(1).Allocate(1, -1); err == nil {
+		t.Errorf("expected error for negative ratio")
+	}
+}
+
+func TestDecimalFloat(t *testing.T) {
+	tests := []struct {
+		input    float64
+		scale    int32
+		mode     RoundingMode
+		expected string
+	}{
+		{0.1, 2, HalfUp, "0.10"},
+		{2.675, 2, HalfUp, "2.68"},
+		{2.675, 2, Floor, "2.67"},
+		{0.1 + 0.2, 2, HalfEven, "0.30"},
+		{-1234.5, -1, HalfUp, "-1230"},
+	}
+
+	for _, tt := range tests {
+		d, err := DecimalFromFloat(tt.input, tt.scale, tt.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.String() != tt.expected {
+			t.Errorf("DecimalFromFloat(%v, %d, %v): expected = %s, got = %s", tt.input, tt.scale, tt.mode, tt.expected, d)
+		}
+	}
+
+	if f := mustDecimal(t, "2.68").Float64(); f != 2.68 {
+		t.Errorf("incorrect float conversion, got = %v", f)
+	}
+	if n, ok := mustDecimal(t, "-12.99").Int64(); n != -12 || !ok {
+		t.Errorf("incorrect integer conversion, got = %d, %v", n, ok)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type invoice struct {
+		Total Decimal `json:"total"`
+		Tax   Decimal `json:"tax"`
+	}
+
+	in := invoice{Total: mustDecimal(t, "1234.50"), Tax: mustDecimal(t, "0.10")}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"total":"1234.50","tax":"0.10"}` {
+		t.Errorf("incorrect JSON, got = %s", data)
+	}
+
+	var out invoice
+	if err := json.Unmarshal([]byte(`{"total":"1234.50","tax":0.1}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Total.String() != "1234.50" || out.Tax.String() != "0.1" {
+		t.Errorf("incorrect decoding, got = %+v", out)
+	}
+}
+
+func TestDecimalSQL(t *testing.T) {
+	v, err := mustDecimal(t, "-9.90").Value()
+	if err != nil || v != "-9.90" {
+		t.Errorf("incorrect driver value, got = %v (%v)", v, err)
+	}
+
+	sources := map[any]string{
+		"12.34":      "12.34",
+		int64(42):    "42",
+		float64(0.1): "0.1",
+		"1e2":        "100",
+	}
+	for src, expected := range sources {
+		var d Decimal
+		if err := d.Scan(src); err != nil || d.String() != expected {
+			t.Errorf("Scan(%v): expected = %s, got = %s (%v)", src, expected, d, err)
+		}
+	}
+
+	var d Decimal
+	if err := d.Scan([]byte("7.5")); err != nil || d.String() != "7.5" {
+		t.Errorf("Scan([]byte): expected = 7.5, got = %s (%v)", d, err)
+	}
+	if err := d.Scan(true); err == nil {
+		t.Errorf("expected error scanning a bool")
+	}
+}
@@ -1,9 +1,52 @@
 // math package provides functions for mathematical operations.
 package math
 
-import "math"
+import (
+	"math"
+	"strconv"
+	"strings"
+)
 
-// Round rounds a float64 value to a specified number of decimal places.
+// RoundingMode selects how a value is rounded when it falls between two
+// representable results.
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest value, ties away from zero (2.5 -> 3, -2.5 -> -3).
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest value, ties to the even neighbour
+	// (2.5 -> 2, 3.5 -> 4). Also known as banker's rounding.
+	HalfEven
+	// HalfDown rounds to the nearest value, ties toward zero (2.5 -> 2, -2.5 -> -2).
+	HalfDown
+	// Ceiling rounds toward positive infinity.
+	Ceiling
+	// Floor rounds toward negative infinity.
+	Floor
+	// Truncate rounds toward zero.
+	Truncate
+)
+
+func (m RoundingMode) String() string {
+	switch m {
+	case HalfUp:
+		return "HalfUp"
+	case HalfEven:
+		return "HalfEven"
+	case HalfDown:
+		return "HalfDown"
+	case Ceiling:
+		return "Ceiling"
+	case Floor:
+		return "Floor"
+	case Truncate:
+		return "Truncate"
+	}
+	return "RoundingMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// Round rounds a float64 value to a specified number of decimal places,
+// with ties rounded away from zero.
 //
 // Parameters:
 //   - value: The float64 value to round.
@@ -11,6 +54,112 @@ import "math"
 //
 // Returns:
 //   - The rounded float64 value.
-func Round(value float64, decimals uint8) float64{
-    return math.Round(value * 10.0 * float64(decimals)) / (10.0 * float64(decimals))
+func Round(value float64, decimals uint8) float64 {
+	return RoundWithMode(value, int(decimals), HalfUp)
+}
+
+// RoundWithMode rounds value to precision decimal places using mode.
+//
+// Rounding works on the shortest decimal representation of value, the one
+// strconv prints, rather than on its binary expansion. So 2.675 rounds to
+// 2.68 with HalfUp even though the nearest float64 is 2.67499999..., and
+// 0.1+0.2 rounds to 0.3. The result is the float64 closest to the rounded
+// decimal.
+//
+// Parameters:
+//   - value: The float64 value to round.
+//   - precision: The number of decimal places to keep. Negative values round
+//     to the left of the decimal point: -1 to tens, -2 to hundreds.
+//   - mode: The rounding mode.
+//
+// Returns:
+//   - The rounded float64 value. NaN and infinities are returned unchanged.
+//
+// Examples:
+//
+//	RoundWithMode(2.5, 0, HalfEven)    // 2
+//	RoundWithMode(1.005, 2, HalfUp)    // 1.01
+//	RoundWithMode(1234, -2, Ceiling)   // 1300
+func RoundWithMode(value float64, precision int, mode RoundingMode) float64 {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	neg := value < 0
+	// d.dddde±x: digits holds the significant digits, point the number of
+	// them that sit before the decimal point
+	s := strconv.FormatFloat(math.Abs(value), 'e', -1, 64)
+	mant, exp, _ := strings.Cut(s, "e")
+	e, _ := strconv.Atoi(exp)
+	digits := []byte(mant[:1])
+	if len(mant) > 2 {
+		digits = append(digits, mant[2:]...)
+	}
+	point := e + 1
+
+	keep := point + precision
+	if keep >= len(digits) {
+		return value
+	}
+
+	var first byte = '0'
+	rest := false
+	if keep >= 0 {
+		first = digits[keep]
+		for _, d := range digits[keep+1:] {
+			rest = rest || d != '0'
+		}
+	} else {
+		// every significant digit is below half a unit
+		rest = true
+	}
+	dropped := first != '0' || rest
+
+	var last byte = '0'
+	if keep > 0 {
+		last = digits[keep-1]
+	}
+
+	var up bool
+	switch mode {
+	case HalfUp:
+		up = first >= '5'
+	case HalfDown:
+		up = first > '5' || (first == '5' && rest)
+	case HalfEven:
+		up = first > '5' || (first == '5' && (rest || (last-'0')%2 == 1))
+	case Ceiling:
+		up = dropped && !neg
+	case Floor:
+		up = dropped && neg
+	case Truncate:
+		up = false
+	}
+
+	kept := digits[:max(keep, 0)]
+	if up {
+		kept = incrementDecimal(kept)
+	}
+	if len(kept) == 0 {
+		return math.Copysign(0, value)
+	}
+
+	r, _ := strconv.ParseFloat(string(kept)+"e"+strconv.Itoa(-precision), 64)
+	if neg {
+		r = -r
+	}
+	return r
+}
+
+// incrementDecimal adds one to a string of decimal digits.
+func incrementDecimal(d []byte) []byte {
+	d = append([]byte(nil), d...)
+	for i := len(d) - 1; i >= 0; i-- {
+		if d[i] < '9' {
+			d[i]++
+			return d
+		}
+		d[i] = '0'
+	}
+	return append([]byte{'1'}, d...)
 }
@@ -0,0 +1,96 @@
+package math
+
+import (
+	stdmath "math"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value    float64
+		decimals uint8
+		expected float64
+	}{
+		{1.2345, 3, 1.235},
+		{2.5, 0, 3},
+		{-2.5, 0, -3},
+		{2.675, 2, 2.68},
+		{1.005, 2, 1.01},
+		{0.1 + 0.2, 2, 0.3},
+		{1.15, 1, 1.2},
+		{9.995, 2, 10},
+		{123.456, 10, 123.456},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.value, tt.decimals); got != tt.expected {
+			t.Errorf("Round(%v, %d): expected = %v, got = %v", tt.value, tt.decimals, tt.expected, got)
+		}
+	}
+}
+
+func TestRoundWithMode(t *testing.T) {
+	tests := []struct {
+		value     float64
+		precision int
+		mode      RoundingMode
+		expected  float64
+	}{
+		// banker's rounding
+		{2.5, 0, HalfEven, 2},
+		{3.5, 0, HalfEven, 4},
+		{-2.5, 0, HalfEven, -2},
+		{0.125, 2, HalfEven, 0.12},
+		{0.135, 2, HalfEven, 0.14},
+		{0.1251, 2, HalfEven, 0.13},
+		{2.345, 2, HalfEven, 2.34},
+		// half down
+		{2.5, 0, HalfDown, 2},
+		{-2.5, 0, HalfDown, -2},
+		{2.51, 0, HalfDown, 3},
+		{1.005, 2, HalfDown, 1},
+		// directed modes
+		{1.001, 2, Ceiling, 1.01},
+		{-1.009, 2, Ceiling, -1},
+		{1.009, 2, Floor, 1},
+		{-1.001, 2, Floor, -1.01},
+		{1.999, 2, Truncate, 1.99},
+		{-1.999, 2, Truncate, -1.99},
+		{0.0000001, 2, Ceiling, 0.01},
+		{0.0000001, 2, HalfUp, 0},
+		// negative precision
+		{1234.5, -2, HalfUp, 1200},
+		{1250, -2, HalfEven, 1200},
+		{1350, -2, HalfEven, 1400},
+		{1250, -2, HalfUp, 1300},
+		{49, -2, HalfUp, 0},
+		{50, -2, HalfUp, 100},
+		{1, -2, Ceiling, 100},
+		{-1, -2, Floor, -100},
+		{1234, -2, Ceiling, 1300},
+		// carries and exact values
+		{999.5, 0, HalfUp, 1000},
+		{0.0005, 3, HalfUp, 0.001},
+		{0.0004, 3, HalfUp, 0},
+		{42, 2, Floor, 42},
+		{1e300, 2, HalfUp, 1e300},
+	}
+
+	for _, tt := range tests {
+		if got := RoundWithMode(tt.value, tt.precision, tt.mode); got != tt.expected {
+			t.Errorf("RoundWithMode(%v, %d, %v): expected = %v, got = %v", tt.value, tt.precision, tt.mode, tt.expected, got)
+		}
+	}
+}
+
+func TestRoundSpecialValues(t *testing.T) {
+	if got := RoundWithMode(stdmath.NaN(), 2, HalfUp); !stdmath.IsNaN(got) {
+		t.Errorf("expected NaN, got = %v", got)
+	}
+	if got := RoundWithMode(stdmath.Inf(-1), 2, Ceiling); !stdmath.IsInf(got, -1) {
+		t.Errorf("expected -Inf, got = %v", got)
+	}
+	if got := RoundWithMode(-0.001, 1, HalfUp); got != 0 || !stdmath.Signbit(got) {
+		t.Errorf("expected negative zero, got = %v", got)
+	}
+}
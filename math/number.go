@@ -0,0 +1,292 @@
+package math
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// Signed is a constraint that permits any signed integer type.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint that permits any unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Float is a constraint that permits any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// ErrOverflow is returned by the checked integer operations when the result
+// does not fit in the operand type.
+var ErrOverflow = errors.New("math: integer overflow")
+
+// Clamp limits v to the range [lo, hi].
+//
+// Parameters:
+//   - v: The value to clamp.
+//   - lo: The lower bound.
+//   - hi: The upper bound, expected to be >= lo.
+//
+// Returns:
+//   - lo if v < lo, hi if v > hi, v otherwise.
+func Clamp[T Number](v, lo, hi T) T {
+	return max(lo, min(v, hi))
+}
+
+// Lerp linearly interpolates between a and b: t = 0 returns a, t = 1 returns
+// b. Values of t outside [0, 1] extrapolate.
+func Lerp[T Float](a, b, t T) T {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns the t for which Lerp(a, b, t) == v. It returns NaN
+// if a == b.
+func InverseLerp[T Float](a, b, v T) T {
+	if a == b {
+		return T(math.NaN())
+	}
+	return (v - a) / (b - a)
+}
+
+// Remap maps v from the range [inMin, inMax] onto [outMin, outMax],
+// for example Remap(0.5, 0, 1, 100, 200) returns 150. Values outside the input
+// range extrapolate; wrap the result in Clamp to limit it.
+func Remap[T Float](v, inMin, inMax, outMin, outMax T) T {
+	return Lerp(outMin, outMax, InverseLerp(inMin, inMax, v))
+}
+
+// Sign returns -1 if v is negative, 1 if it is positive and 0 otherwise,
+// including for NaN.
+func Sign[T Number](v T) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// Abs returns the absolute value of an integer. The most negative value of a
+// signed type has no positive counterpart and is returned unchanged.
+func Abs[T Integer](v T) T {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func isSigned[T Integer]() bool {
+	var zero T
+	return zero-1 < 0
+}
+
+// Add returns a + b, or ErrOverflow if the sum does not fit in T.
+func Add[T Integer](a, b T) (T, error) {
+	s := a + b
+	if isSigned[T]() {
+		if (a > 0 && b > 0 && s < 0) || (a < 0 && b < 0 && s >= 0) {
+			return 0, ErrOverflow
+		}
+	} else if s < a {
+		return 0, ErrOverflow
+	}
+	return s, nil
+}
+
+// Mul returns a * b, or ErrOverflow if the product does not fit in T.
+func Mul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	p := a * b
+	if isSigned[T]() {
+		// the most negative value times -1 wraps back to itself; it is the
+		// only negative value whose negation is still negative
+		minusOne := T(0) - 1
+		if (a == minusOne && b < 0 && -b < 0) || (b == minusOne && a < 0 && -a < 0) {
+			return 0, ErrOverflow
+		}
+	}
+	if p/b != a {
+		return 0, ErrOverflow
+	}
+	return p, nil
+}
+
+// Pow returns base raised to exp using exponentiation by squaring, or
+// ErrOverflow if the result does not fit in T.
+func Pow[T Integer](base T, exp uint) (T, error) {
+	result := T(1)
+	for exp > 0 {
+		var err error
+		if exp&1 == 1 {
+			if result, err = Mul(result, base); err != nil {
+				return 0, err
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, err = Mul(base, base); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// GCD returns the greatest common divisor of a and b, non-negative except in
+// one case: when the divisor is the magnitude of the most negative value of a
+// signed type, which has no positive counterpart. GCD(math.MinInt64, 0) and
+// GCD(math.MinInt64, math.MinInt64) return math.MinInt64, as Abs does.
+// GCD(0, 0) is 0.
+func GCD[T Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return Abs(a)
+}
+
+// LCM returns the least common multiple of a and b, always non-negative.
+// It returns 0 if either argument is 0 and wraps around on overflow; use
+// Mul on the result of GCD to detect that.
+func LCM[T Integer](a, b T) T {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return Abs(a / GCD(a, b) * b)
+}
+
+// DivMod returns the quotient and remainder of a / b with floor semantics:
+// the quotient is rounded toward negative infinity and the remainder has the
+// sign of b, so DivMod(-7, 2) returns (-4, 1). Go's / and % truncate toward
+// zero instead. It panics if b is 0.
+func DivMod[T Integer](a, b T) (q, r T) {
+	q, r = a/b, a%b
+	if r != 0 && (r < 0) != (b < 0) {
+		q--
+		r += b
+	}
+	return q, r
+}
+
+// NearlyEqual reports whether a and b are equal within a relative tolerance,
+// scaled by the larger magnitude, or an absolute tolerance, whichever is
+// larger. The absolute tolerance matters near zero, where relative error
+// is meaningless.
+//
+// Parameters:
+//   - a, b: The values to compare.
+//   - relTol: The relative tolerance, e.g. 1e-9.
+//   - absTol: The absolute tolerance, e.g. 1e-12.
+//
+// Returns:
+//   - true if |a-b| <= max(relTol*max(|a|, |b|), absTol). Infinities are
+//     only equal to themselves and NaN is never equal to anything.
+func NearlyEqual[T Float](a, b, relTol, absTol T) bool {
+	if a == b {
+		return true
+	}
+	fa, fb := float64(a), float64(b)
+	if math.IsInf(fa, 0) || math.IsInf(fb, 0) {
+		return false
+	}
+	diff := math.Abs(fa - fb)
+	return diff <= math.Max(float64(relTol)*math.Max(math.Abs(fa), math.Abs(fb)), float64(absTol))
+}
+
+// Sieve returns every prime number up to and including limit, in ascending
+// order, using the sieve of Eratosthenes.
+func Sieve(limit int) []int {
+	if limit < 2 {
+		return nil
+	}
+	composite := make([]bool, limit+1)
+	var primes []int
+	for i := 2; i <= limit; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= limit; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// smallPrimes seeds trial division in IsPrime and doubles as the set of
+// Miller-Rabin witnesses that is deterministic for all 64-bit integers.
+var smallPrimes = Sieve(37)
+
+// IsPrime reports whether n is a prime number. It uses trial division by
+// small primes followed by a deterministic Miller-Rabin test, so it is exact
+// for every 64-bit value.
+func IsPrime[T Integer](n T) bool {
+	if n < 2 {
+		return false
+	}
+	u := uint64(n)
+	for _, p := range smallPrimes {
+		if u%uint64(p) == 0 {
+			return u == uint64(p)
+		}
+	}
+
+	d, s := u-1, 0
+	for d%2 == 0 {
+		d /= 2
+		s++
+	}
+	for _, a := range smallPrimes {
+		x := powMod(uint64(a), d, u)
+		if x == 1 || x == u-1 {
+			continue
+		}
+		composite := true
+		for range s - 1 {
+			x = mulMod(x, x, u)
+			if x == u-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+func powMod(b, e, m uint64) uint64 {
+	r := uint64(1)
+	b %= m
+	for e > 0 {
+		if e&1 == 1 {
+			r = mulMod(r, b, m)
+		}
+		b = mulMod(b, b, m)
+		e >>= 1
+	}
+	return r
+}
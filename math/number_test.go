@@ -0,0 +1,163 @@
+package math
+
+import (
+	"errors"
+	stdmath "math"
+	"slices"
+	"testing"
+)
+
+func TestClampLerp(t *testing.T) {
+	if Clamp(5, 0, 3) != 3 || Clamp(-1, 0, 3) != 0 || Clamp(2.5, 0, 3) != 2.5 {
+		t.Errorf("incorrect Clamp results")
+	}
+	if got := Lerp(10.0, 20.0, 0.25); got != 12.5 {
+		t.Errorf("Lerp: expected = 12.5, got = %v", got)
+	}
+	if got := InverseLerp(10.0, 20.0, 12.5); got != 0.25 {
+		t.Errorf("InverseLerp: expected = 0.25, got = %v", got)
+	}
+	if got := InverseLerp(1.0, 1.0, 1.0); !stdmath.IsNaN(got) {
+		t.Errorf("InverseLerp: expected NaN for an empty range, got = %v", got)
+	}
+	if got := Remap(float32(0.5), 0, 1, 100, 200); got != 150 {
+		t.Errorf("Remap: expected = 150, got = %v", got)
+	}
+	if got := Remap(-10.0, -20, 0, 1, 0); got != 0.5 {
+		t.Errorf("Remap: expected = 0.5, got = %v", got)
+	}
+}
+
+func TestSignAbs(t *testing.T) {
+	if Sign(-3) != -1 || Sign(0.0) != 0 || Sign(uint8(7)) != 1 || Sign(stdmath.NaN()) != 0 {
+		t.Errorf("incorrect Sign results")
+	}
+	if Abs(-5) != 5 || Abs(int8(-128)) != -128 || Abs(uint(3)) != 3 {
+		t.Errorf("incorrect Abs results")
+	}
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      func() (int8, error)
+		expected int8
+		overflow bool
+	}{
+		{"Add", func() (int8, error) { return Add[int8](100, 27) }, 127, false},
+		{"Add overflow", func() (int8, error) { return Add[int8](100, 28) }, 0, true},
+		{"Add underflow", func() (int8, error) { return Add[int8](-100, -29) }, 0, true},
+		{"Mul", func() (int8, error) { return Mul[int8](-16, 8) }, -128, false},
+		{"Mul overflow", func() (int8, error) { return Mul[int8](16, 8) }, 0, true},
+		{"Mul min by -1", func() (int8, error) { return Mul[int8](-128, -1) }, 0, true},
+		{"Mul -1 by min", func() (int8, error) { return Mul[int8](-1, -128) }, 0, true},
+		{"Pow", func() (int8, error) { return Pow[int8](-2, 7) }, -128, false},
+		{"Pow overflow", func() (int8, error) { return Pow[int8](2, 7) }, 0, true},
+		{"Pow zero", func() (int8, error) { return Pow[int8](0, 0) }, 1, false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.got()
+		if tt.overflow != errors.Is(err, ErrOverflow) || got != tt.expected {
+			t.Errorf("%s: expected = %d (overflow %v), got = %d (%v)", tt.name, tt.expected, tt.overflow, got, err)
+		}
+	}
+
+	if _, err := Add[uint8](200, 56); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected unsigned Add overflow, got %v", err)
+	}
+	if v, err := Pow[uint64](3, 40); err != nil || v != 12157665459056928801 {
+		t.Errorf("Pow(3, 40): expected = 12157665459056928801, got = %d (%v)", v, err)
+	}
+	if _, err := Pow[uint64](3, 41); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected Pow(3, 41) to overflow, got %v", err)
+	}
+}
+
+func TestGCDLCM(t *testing.T) {
+	if GCD(48, 18) != 6 || GCD(-48, 18) != 6 || GCD(0, 5) != 5 || GCD(0, 0) != 0 {
+		t.Errorf("incorrect GCD results")
+	}
+	// 2^63 has no positive int64 counterpart
+	if GCD(stdmath.MinInt64, 0) != stdmath.MinInt64 || GCD(0, int64(stdmath.MinInt64)) != stdmath.MinInt64 ||
+		GCD(int64(stdmath.MinInt64), stdmath.MinInt64) != stdmath.MinInt64 {
+		t.Errorf("GCD must return MinInt64 when the divisor is 2^63")
+	}
+	if GCD(stdmath.MinInt64, 6) != 2 || GCD(int8(-128), 96) != 32 {
+		t.Errorf("incorrect GCD results for the most negative value")
+	}
+	if LCM(4, 6) != 12 || LCM(-4, 6) != 12 || LCM(0, 6) != 0 {
+		t.Errorf("incorrect LCM results")
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	tests := []struct{ a, b, q, r int }{
+		{7, 2, 3, 1},
+		{-7, 2, -4, 1},
+		{7, -2, -4, -1},
+		{-7, -2, 3, -1},
+		{6, 3, 2, 0},
+		{-6, 3, -2, 0},
+	}
+
+	for _, tt := range tests {
+		if q, r := DivMod(tt.a, tt.b); q != tt.q || r != tt.r {
+			t.Errorf("DivMod(%d, %d): expected = (%d, %d), got = (%d, %d)", tt.a, tt.b, tt.q, tt.r, q, r)
+		}
+	}
+}
+
+func TestNearlyEqual(t *testing.T) {
+	tests := []struct {
+		a, b     float64
+		expected bool
+	}{
+		{0.1 + 0.2, 0.3, true},
+		{1e9, 1e9 + 1, true},
+		{1e-20, 0, true},
+		{1, 1.001, false},
+		{stdmath.Inf(1), stdmath.Inf(1), true},
+		{stdmath.Inf(1), stdmath.MaxFloat64, false},
+		{stdmath.NaN(), stdmath.NaN(), false},
+	}
+
+	for _, tt := range tests {
+		if got := NearlyEqual(tt.a, tt.b, 1e-9, 1e-12); got != tt.expected {
+			t.Errorf("NearlyEqual(%v, %v): expected = %v, got = %v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func TestPrimes(t *testing.T) {
+	if got := Sieve(30); !slices.Equal(got, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}) {
+		t.Errorf("incorrect sieve, got = %v", got)
+	}
+	if Sieve(1) != nil {
+		t.Errorf("expected no primes below 2")
+	}
+
+	primes := Sieve(10_000)
+	for n := -5; n <= 10_000; n++ {
+		_, found := slices.BinarySearch(primes, n)
+		if IsPrime(n) != found {
+			t.Errorf("IsPrime(%d): expected = %v", n, found)
+		}
+	}
+
+	large := []struct {
+		n        uint64
+		expected bool
+	}{
+		{18446744073709551557, true}, // largest 64-bit prime
+		{18446744073709551615, false},
+		{3215031751, false},          // strong pseudoprime to bases 2, 3, 5, 7
+		{3825123056546413051, false}, // strong pseudoprime to bases up to 23
+		{2305843009213693951, true},  // Mersenne prime 2^61-1
+	}
+	for _, tt := range large {
+		if IsPrime(tt.n) != tt.expected {
+			t.Errorf("IsPrime(%d): expected = %v", tt.n, tt.expected)
+		}
+	}
+}
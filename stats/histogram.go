@@ -0,0 +1,328 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	gbytes "github.com/AntonyChR/go-utils/bytes"
+)
+
+// ErrOutOfRange is returned when recording a value outside the trackable
+// range of a Histogram.
+var ErrOutOfRange = errors.New("stats: value out of histogram range")
+
+// Histogram is a log-bucketed histogram of integer values in the style of
+// Gil Tene's HdrHistogram, typically used for latencies.
+//
+// Accuracy: values are tracked with a configurable number of significant
+// decimal digits. Every value reported for a quantile, and every bucket
+// boundary, is within a relative error of 10^-digits of a recorded value at
+// that rank; with 3 digits, 1,000,000µs may be reported as at most 1,000,999µs.
+//
+// Memory depends only on the configured range and precision, not on the
+// number of values recorded. Histograms can be serialized and merged, even
+// when their configurations differ.
+type Histogram struct {
+	lowest, highest int64
+	digits          int
+
+	unitMagnitude      int
+	subBucketHalfMag   int
+	subBucketCount     int
+	subBucketHalfCount int
+	subBucketMask      int64
+
+	counts   []uint64
+	total    uint64
+	min, max int64
+}
+
+// NewHistogram creates a histogram that tracks values in [lowest, highest]
+// with the given number of significant decimal digits.
+//
+// Parameters:
+//   - lowest: The smallest value that can be told apart from 0, at least 1.
+//     Use 1 for full precision down to single units.
+//   - highest: The largest trackable value, at least 2·lowest.
+//   - digits: The number of significant decimal digits, between 1 and 5.
+//
+// Returns:
+//   - An error if the configuration is invalid.
+func NewHistogram(lowest, highest int64, digits int) (*Histogram, error) {
+	if lowest < 1 {
+		return nil, fmt.Errorf("stats: lowest trackable value must be >= 1, got %d", lowest)
+	}
+	// 2·lowest could overflow, so halve highest instead
+	if lowest > highest/2 {
+		return nil, fmt.Errorf("stats: highest trackable value must be >= 2*lowest, got %d", highest)
+	}
+	if digits < 1 || digits > 5 {
+		return nil, fmt.Errorf("stats: significant digits must be between 1 and 5, got %d", digits)
+	}
+
+	h := &Histogram{lowest: lowest, highest: highest, digits: digits}
+
+	// enough sub-buckets to tell apart 2·10^digits values in each bucket
+	largestSingleUnit := 2 * int64(math.Pow10(digits))
+	subBucketCountMag := bits.Len64(uint64(largestSingleUnit - 1))
+	h.subBucketHalfMag = subBucketCountMag - 1
+	h.unitMagnitude = bits.Len64(uint64(lowest)) - 1
+	h.subBucketCount = 1 << subBucketCountMag
+	h.subBucketHalfCount = h.subBucketCount / 2
+	h.subBucketMask = int64(h.subBucketCount-1) << h.unitMagnitude
+
+	bucketCount := 1
+	smallestUntrackable := int64(h.subBucketCount) << h.unitMagnitude
+	for smallestUntrackable <= highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			bucketCount++
+			break
+		}
+		smallestUntrackable <<= 1
+		bucketCount++
+	}
+	h.counts = make([]uint64, (bucketCount+1)*h.subBucketHalfCount)
+	h.Reset()
+	return h, nil
+}
+
+// Reset removes every recorded value, keeping the configuration.
+func (h *Histogram) Reset() {
+	clear(h.counts)
+	h.total = 0
+	h.min, h.max = math.MaxInt64, 0
+}
+
+func (h *Histogram) countsIndex(v int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(v|h.subBucketMask))
+	bucket := pow2Ceiling - h.unitMagnitude - (h.subBucketHalfMag + 1)
+	sub := int(v >> uint(bucket+h.unitMagnitude))
+	return (bucket+1)<<h.subBucketHalfMag + sub - h.subBucketHalfCount
+}
+
+// bucketOf splits a counts index into its bucket and sub-bucket.
+func (h *Histogram) bucketOf(i int) (bucket, sub int) {
+	bucket = i>>h.subBucketHalfMag - 1
+	sub = i&(h.subBucketHalfCount-1) + h.subBucketHalfCount
+	if bucket < 0 {
+		sub -= h.subBucketHalfCount
+		bucket = 0
+	}
+	return bucket, sub
+}
+
+// lowestEquivalent returns the smallest value sharing bucket i.
+func (h *Histogram) lowestEquivalent(i int) int64 {
+	bucket, sub := h.bucketOf(i)
+	return int64(sub) << uint(bucket+h.unitMagnitude)
+}
+
+// highestEquivalent returns the largest value sharing bucket i.
+func (h *Histogram) highestEquivalent(i int) int64 {
+	bucket, _ := h.bucketOf(i)
+	return h.lowestEquivalent(i) + int64(1)<<uint(bucket+h.unitMagnitude) - 1
+}
+
+// Record adds a single occurrence of v.
+//
+// Returns:
+//   - ErrOutOfRange if v is negative or greater than the highest trackable value.
+func (h *Histogram) Record(v int64) error {
+	return h.RecordN(v, 1)
+}
+
+// RecordN adds n occurrences of v. It follows the same conventions as Record.
+func (h *Histogram) RecordN(v int64, n uint64) error {
+	if v < 0 || v > h.highest {
+		return fmt.Errorf("%w: %d not in [0, %d]", ErrOutOfRange, v, h.highest)
+	}
+	if n == 0 {
+		return nil
+	}
+	h.counts[h.countsIndex(v)] += n
+	h.total += n
+	h.min = min(h.min, v)
+	h.max = max(h.max, v)
+	return nil
+}
+
+// Count returns the number of values recorded.
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Min returns the exact smallest value recorded, or 0 if there are none.
+func (h *Histogram) Min() int64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.min
+}
+
+// Max returns the exact largest value recorded, or 0 if there are none.
+func (h *Histogram) Max() int64 {
+	return h.max
+}
+
+// Mean returns the mean of the recorded values, computed from bucket
+// midpoints, or NaN if there are none.
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return math.NaN()
+	}
+	acc := 0.0
+	for i, c := range h.counts {
+		if c > 0 {
+			mid := (h.lowestEquivalent(i) + h.highestEquivalent(i)) / 2
+			acc += float64(mid) * float64(c)
+		}
+	}
+	return acc / float64(h.total)
+}
+
+// ValueAtQuantile returns the q-quantile: the highest value equivalent to
+// the recorded value at rank ceil(q·n), clamped to the exact maximum.
+//
+// Returns:
+//   - The quantile, or 0 if the histogram is empty.
+func (h *Histogram) ValueAtQuantile(q float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+	q = math.Max(0, math.Min(q, 1))
+	target := max(1, uint64(math.Ceil(q*float64(h.total))))
+
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			return min(h.highestEquivalent(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// Merge adds every value recorded in o to h. When the configurations differ,
+// values are transferred at the midpoint of their buckets, so precision is
+// that of the coarser histogram.
+//
+// Returns:
+//   - ErrOutOfRange if o holds values beyond the range of h; h is not
+//     modified in that case.
+func (h *Histogram) Merge(o *Histogram) error {
+	if o.total > 0 && o.max > h.highest {
+		return fmt.Errorf("%w: maximum %d exceeds %d", ErrOutOfRange, o.max, h.highest)
+	}
+
+	same := h.lowest == o.lowest && h.digits == o.digits
+	for i, c := range o.counts {
+		if c == 0 {
+			continue
+		}
+		if same && i < len(h.counts) {
+			h.counts[i] += c
+		} else {
+			v := min((o.lowestEquivalent(i)+o.highestEquivalent(i))/2, h.highest)
+			h.counts[h.countsIndex(v)] += c
+		}
+	}
+	if o.total > 0 {
+		h.total += o.total
+		h.min = min(h.min, o.min)
+		h.max = max(h.max, o.max)
+	}
+	return nil
+}
+
+const histogramVersion = 1
+
+// MarshalBinary encodes the histogram configuration and its non-empty
+// buckets in a compact varint-based format.
+func (h *Histogram) MarshalBinary() ([]byte, error) {
+	buf := []byte{histogramVersion}
+	buf = gbytes.AppendUvarint(buf, uint64(h.lowest))
+	buf = gbytes.AppendUvarint(buf, uint64(h.highest))
+	buf = gbytes.AppendUvarint(buf, uint64(h.digits))
+	buf = gbytes.AppendUvarint(buf, uint64(h.min))
+	buf = gbytes.AppendUvarint(buf, uint64(h.max))
+
+	// pairs of (gap since the previous non-empty bucket, count)
+	last := -1
+	for i, c := range h.counts {
+		if c > 0 {
+			buf = gbytes.AppendUvarint(buf, uint64(i-last))
+			buf = gbytes.AppendUvarint(buf, c)
+			last = i
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces h with a histogram decoded from data produced by
+// MarshalBinary.
+//
+// Returns:
+//   - ErrCorrupt if data is malformed, including buckets outside the range,
+//     a total count that overflows, and a minimum or maximum outside
+//     [0, highest] or outside the first or last non-empty bucket.
+func (h *Histogram) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != histogramVersion {
+		return fmt.Errorf("%w: unknown version", ErrCorrupt)
+	}
+	d := decoder{buf: data[1:]}
+	lowest, highest, digits := d.uvarint(), d.uvarint(), d.uvarint()
+	minVal, maxVal := d.uvarint(), d.uvarint()
+	if d.err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, d.err)
+	}
+	if lowest > math.MaxInt64 || highest > math.MaxInt64 || digits > 5 {
+		return fmt.Errorf("%w: invalid configuration", ErrCorrupt)
+	}
+
+	decoded, err := NewHistogram(int64(lowest), int64(highest), int(digits))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	first, i := -1, -1
+	for len(d.buf) > 0 {
+		gap, c := d.uvarint(), d.uvarint()
+		if d.err != nil {
+			return fmt.Errorf("%w: %v", ErrCorrupt, d.err)
+		}
+		if gap == 0 || gap > uint64(len(decoded.counts)) || i+int(gap) >= len(decoded.counts) {
+			return fmt.Errorf("%w: bucket index out of range", ErrCorrupt)
+		}
+		// MarshalBinary only writes non-empty buckets
+		if c == 0 {
+			return fmt.Errorf("%w: empty bucket", ErrCorrupt)
+		}
+		if decoded.total+c < decoded.total {
+			return fmt.Errorf("%w: total count overflows", ErrCorrupt)
+		}
+		i += int(gap)
+		if first < 0 {
+			first = i
+		}
+		decoded.counts[i] = c
+		decoded.total += c
+	}
+	if decoded.total > 0 {
+		// the exact extremes clamp the quantiles, so they must agree with the
+		// buckets they were recorded in
+		if minVal > maxVal || maxVal > uint64(decoded.highest) {
+			return fmt.Errorf("%w: min %d and max %d not in [0, %d]", ErrCorrupt, minVal, maxVal, decoded.highest)
+		}
+		decoded.min, decoded.max = int64(minVal), int64(maxVal)
+		if decoded.min < decoded.lowestEquivalent(first) || decoded.min > decoded.highestEquivalent(first) {
+			return fmt.Errorf("%w: min %d outside the first non-empty bucket", ErrCorrupt, minVal)
+		}
+		if decoded.max < decoded.lowestEquivalent(i) || decoded.max > decoded.highestEquivalent(i) {
+			return fmt.Errorf("%w: max %d outside the last non-empty bucket", ErrCorrupt, maxVal)
+		}
+	}
+	*h = *decoded
+	return nil
+}
@@ -0,0 +1,339 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	gbytes "github.com/AntonyChR/go-utils/bytes"
+)
+
+var (
+	// ErrIncompatible is returned when merging sketches or histograms whose
+	// configurations cannot be combined.
+	ErrIncompatible = errors.New("stats: incompatible configurations")
+	// ErrCorrupt is returned when decoding malformed serialized data.
+	ErrCorrupt = errors.New("stats: corrupt serialized data")
+)
+
+// minIndexable is the smallest magnitude tracked by its own bucket; smaller
+// values, including zero, are counted in the zero bucket.
+const minIndexable = 0x1p-1022
+
+// DDSketch is a mergeable quantile sketch with relative-error guarantees,
+// after Masson, Rim and Lee, "DDSketch: A Fast and Fully-Mergeable Quantile
+// Sketch with Relative-Error Guarantees" (VLDB 2019).
+//
+// Accuracy: for a sketch created with relative accuracy α, every quantile
+// returned is within α·|v| of the exact value v at the same rank, for any
+// input distribution. Values closer to zero than 2.2e-308 are reported as 0.
+//
+// Memory grows with the logarithm of the ratio between the largest and
+// smallest magnitudes added, about ln(max/min)/(2α) buckets, independently of
+// the number of values. Sketches built with the same accuracy in several
+// processes can be serialized, shipped and merged without loss.
+//
+// The zero value is not usable: create sketches with NewDDSketch, or decode
+// them with UnmarshalBinary.
+type DDSketch struct {
+	alpha    float64
+	gamma    float64
+	logGamma float64
+
+	pos, neg denseStore
+	zero     uint64
+	count    uint64
+	sum      float64
+	min, max float64
+}
+
+// denseStore holds bucket counts for a contiguous range of indexes.
+type denseStore struct {
+	offset int
+	counts []uint64
+}
+
+func (s *denseStore) add(i int, n uint64) {
+	if len(s.counts) == 0 {
+		s.offset = i
+		s.counts = []uint64{0}
+	}
+	if i < s.offset {
+		grown := make([]uint64, len(s.counts)+s.offset-i)
+		copy(grown[s.offset-i:], s.counts)
+		s.counts, s.offset = grown, i
+	}
+	if i >= s.offset+len(s.counts) {
+		s.counts = append(s.counts, make([]uint64, i-s.offset-len(s.counts)+1)...)
+	}
+	s.counts[i-s.offset] += n
+}
+
+// NewDDSketch creates an empty sketch whose quantiles are accurate to within
+// the given relative accuracy, for example 0.01 for 1%.
+//
+// Returns:
+//   - An error if relativeAccuracy is not strictly between 0 and 1.
+func NewDDSketch(relativeAccuracy float64) (*DDSketch, error) {
+	if !(relativeAccuracy > 0 && relativeAccuracy < 1) {
+		return nil, fmt.Errorf("stats: relative accuracy must be in (0, 1), got %v", relativeAccuracy)
+	}
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	if gamma <= 1 {
+		return nil, fmt.Errorf("stats: relative accuracy %v is too small", relativeAccuracy)
+	}
+	return &DDSketch{
+		alpha:    relativeAccuracy,
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		min:      math.Inf(1),
+		max:      math.Inf(-1),
+	}, nil
+}
+
+// RelativeAccuracy returns the accuracy the sketch was created with.
+func (s *DDSketch) RelativeAccuracy() float64 {
+	return s.alpha
+}
+
+func (s *DDSketch) index(x float64) int {
+	return int(math.Ceil(math.Log(x) / s.logGamma))
+}
+
+// keyRange returns the bounds of the bucket indexes of the magnitudes the
+// sketch tracks, from minIndexable to the largest float64.
+func (s *DDSketch) keyRange() (lo, hi int) {
+	return s.index(minIndexable), s.index(math.MaxFloat64)
+}
+
+// value returns the representative of bucket i, the point that minimizes the
+// relative error over (gamma^(i-1), gamma^i].
+func (s *DDSketch) value(i int) float64 {
+	return 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+}
+
+// Add records x. NaN values are ignored.
+func (s *DDSketch) Add(x float64) {
+	s.AddN(x, 1)
+}
+
+// AddN records n occurrences of x. NaN values are ignored.
+func (s *DDSketch) AddN(x float64, n uint64) {
+	if n == 0 || math.IsNaN(x) {
+		return
+	}
+	switch {
+	case x > minIndexable:
+		s.pos.add(s.index(x), n)
+	case x < -minIndexable:
+		s.neg.add(s.index(-x), n)
+	default:
+		s.zero += n
+	}
+	s.count += n
+	s.sum += x * float64(n)
+	s.min = math.Min(s.min, x)
+	s.max = math.Max(s.max, x)
+}
+
+// Count returns the number of values recorded.
+func (s *DDSketch) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the sum of the values recorded.
+func (s *DDSketch) Sum() float64 {
+	return s.sum
+}
+
+// Mean returns the mean of the values recorded, or NaN if there are none.
+func (s *DDSketch) Mean() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.sum / float64(s.count)
+}
+
+// Min returns the exact smallest value recorded, or NaN if there are none.
+func (s *DDSketch) Min() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.min
+}
+
+// Max returns the exact largest value recorded, or NaN if there are none.
+func (s *DDSketch) Max() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.max
+}
+
+// Quantile returns an estimate of the q-quantile, the value at rank
+// floor(q·(n-1)) of the sorted input.
+//
+// Returns:
+//   - The estimate, or NaN if the sketch is empty or q is outside [0, 1].
+func (s *DDSketch) Quantile(q float64) float64 {
+	if s.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+	var v float64
+	found := false
+
+	// negative values, from the most negative bucket up
+	for i := len(s.neg.counts) - 1; i >= 0 && !found; i-- {
+		seen += s.neg.counts[i]
+		if seen > rank {
+			v, found = -s.value(i+s.neg.offset), true
+		}
+	}
+	if !found {
+		seen += s.zero
+		if seen > rank {
+			v, found = 0, true
+		}
+	}
+	for i := 0; i < len(s.pos.counts) && !found; i++ {
+		seen += s.pos.counts[i]
+		if seen > rank {
+			v, found = s.value(i+s.pos.offset), true
+		}
+	}
+
+	// the exact extremes are tighter than any bucket representative
+	return math.Max(s.min, math.Min(v, s.max))
+}
+
+// Merge adds every value recorded in o to s.
+//
+// Returns:
+//   - ErrIncompatible if o was created with a different relative accuracy.
+func (s *DDSketch) Merge(o *DDSketch) error {
+	if o.alpha != s.alpha {
+		return fmt.Errorf("%w: relative accuracy %v and %v", ErrIncompatible, s.alpha, o.alpha)
+	}
+	for i, c := range o.pos.counts {
+		if c > 0 {
+			s.pos.add(i+o.pos.offset, c)
+		}
+	}
+	for i, c := range o.neg.counts {
+		if c > 0 {
+			s.neg.add(i+o.neg.offset, c)
+		}
+	}
+	s.zero += o.zero
+	s.count += o.count
+	s.sum += o.sum
+	s.min = math.Min(s.min, o.min)
+	s.max = math.Max(s.max, o.max)
+	return nil
+}
+
+const ddSketchVersion = 1
+
+// MarshalBinary encodes the sketch in a compact varint-based format.
+func (s *DDSketch) MarshalBinary() ([]byte, error) {
+	buf := []byte{ddSketchVersion}
+	for _, f := range []float64{s.alpha, s.sum, s.min, s.max} {
+		buf = gbytes.AppendUvarint(buf, math.Float64bits(f))
+	}
+	buf = gbytes.AppendUvarint(buf, s.zero)
+	for _, st := range []*denseStore{&s.pos, &s.neg} {
+		buf = gbytes.AppendVarint(buf, int64(st.offset))
+		buf = gbytes.AppendUvarint(buf, uint64(len(st.counts)))
+		for _, c := range st.counts {
+			buf = gbytes.AppendUvarint(buf, c)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces s with a sketch decoded from data produced by
+// MarshalBinary.
+//
+// Returns:
+//   - ErrCorrupt if data is malformed, including buckets outside the range
+//     of indexes its relative accuracy allows.
+func (s *DDSketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != ddSketchVersion {
+		return fmt.Errorf("%w: unknown version", ErrCorrupt)
+	}
+	d := decoder{buf: data[1:]}
+
+	var floats [4]float64
+	for i := range floats {
+		floats[i] = math.Float64frombits(d.uvarint())
+	}
+	zero := d.uvarint()
+
+	var stores [2]denseStore
+	count := zero
+	for i := range stores {
+		stores[i].offset = int(d.varint())
+		n := d.uvarint()
+		if d.err == nil && n > uint64(len(d.buf)) {
+			return fmt.Errorf("%w: store length %d exceeds input", ErrCorrupt, n)
+		}
+		stores[i].counts = make([]uint64, n)
+		for j := range stores[i].counts {
+			stores[i].counts[j] = d.uvarint()
+			count += stores[i].counts[j]
+		}
+	}
+	if d.err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, d.err)
+	}
+
+	decoded, err := NewDDSketch(floats[0])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	// crafted offsets could otherwise make Merge allocate billions of
+	// buckets, or overflow the indexes
+	lo, hi := decoded.keyRange()
+	for i := range stores {
+		st := &stores[i]
+		if len(st.counts) == 0 {
+			st.offset = 0
+			continue
+		}
+		if st.offset < lo || st.offset > hi || len(st.counts) > hi-st.offset+1 {
+			return fmt.Errorf("%w: %d buckets from index %d outside [%d, %d]", ErrCorrupt, len(st.counts), st.offset, lo, hi)
+		}
+	}
+
+	decoded.sum, decoded.min, decoded.max = floats[1], floats[2], floats[3]
+	decoded.zero, decoded.count = zero, count
+	decoded.pos, decoded.neg = stores[0], stores[1]
+	*s = *decoded
+	return nil
+}
+
+// decoder reads varints from a buffer, remembering the first error.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n, err := gbytes.Uvarint(d.buf)
+	if err != nil {
+		d.err = err
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	return gbytes.ZigZagDecode(d.uvarint())
+}
@@ -0,0 +1,334 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	gbytes "github.com/AntonyChR/go-utils/bytes"
+)
+
+var testQuantiles = []float64{0, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1}
+
+func TestDDSketchErrorBound(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 11))
+	const alpha = 0.01
+
+	distributions := map[string]func() float64{
+		"lognormal": func() float64 { return math.Exp(r.NormFloat64() * 2) },
+		"uniform":   func() float64 { return r.Float64() * 1000 },
+		"normal":    func() float64 { return r.NormFloat64() * 50 },
+		"pareto":    func() float64 { return math.Pow(r.Float64(), -1/1.5) },
+	}
+
+	for name, gen := range distributions {
+		s, err := NewDDSketch(alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xs := make([]float64, 50_000)
+		for i := range xs {
+			xs[i] = gen()
+			s.Add(xs[i])
+		}
+		if name == "normal" {
+			s.Add(0)
+			xs = append(xs, 0)
+		}
+		slices.Sort(xs)
+
+		for _, q := range testQuantiles {
+			exact := xs[int(q*float64(len(xs)-1))]
+			got := s.Quantile(q)
+			if math.Abs(got-exact) > alpha*math.Abs(exact)+1e-12 {
+				t.Errorf("%s q=%v: estimate %v exceeds %v%% of exact %v", name, q, got, alpha*100, exact)
+			}
+		}
+		if s.Count() != uint64(len(xs)) || s.Min() != xs[0] || s.Max() != xs[len(xs)-1] {
+			t.Errorf("%s: incorrect count or extremes", name)
+		}
+	}
+}
+
+func TestDDSketchMergeAndSerialize(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 5))
+	whole, _ := NewDDSketch(0.02)
+	var xs []float64
+
+	// three processes each build a sketch, ship it, and the collector merges
+	collector, _ := NewDDSketch(0.02)
+	for range 3 {
+		part, _ := NewDDSketch(0.02)
+		for range 10_000 {
+			x := r.ExpFloat64()*100 - 20
+			part.Add(x)
+			whole.Add(x)
+			xs = append(xs, x)
+		}
+
+		data, err := part.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var shipped DDSketch
+		if err := shipped.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := collector.Merge(&shipped); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Sort(xs)
+
+	for _, q := range testQuantiles {
+		if got, expected := collector.Quantile(q), whole.Quantile(q); got != expected {
+			t.Errorf("q=%v: merged sketch differs, expected = %v, got = %v", q, expected, got)
+		}
+		exact := xs[int(q*float64(len(xs)-1))]
+		if got := collector.Quantile(q); math.Abs(got-exact) > 0.02*math.Abs(exact)+1e-12 {
+			t.Errorf("q=%v: estimate %v too far from exact %v", q, got, exact)
+		}
+	}
+	if math.Abs(collector.Mean()-whole.Mean()) > 1e-9 {
+		t.Errorf("incorrect merged mean, expected = %v, got = %v", whole.Mean(), collector.Mean())
+	}
+
+	other, _ := NewDDSketch(0.05)
+	if err := collector.Merge(other); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("expected ErrIncompatible, got %v", err)
+	}
+	var bad DDSketch
+	if err := bad.UnmarshalBinary([]byte{1, 0xff}); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestDDSketchCorruptStores(t *testing.T) {
+	s, _ := NewDDSketch(0.02)
+	// the extreme magnitudes use the first and last valid indexes
+	s.Add(math.MaxFloat64)
+	s.Add(-math.SmallestNonzeroFloat64 * 0x1p52)
+	data, _ := s.MarshalBinary()
+	var extremes DDSketch
+	if err := extremes.UnmarshalBinary(data); err != nil {
+		t.Fatalf("extreme values must round trip: %v", err)
+	}
+
+	lo, hi := s.keyRange()
+	tests := []struct {
+		name   string
+		offset int
+		n      int
+	}{
+		{"huge offset", 1 << 40, 1},
+		{"negative offset", -(1 << 40), 1},
+		{"below the range", lo - 1, 1},
+		{"past the range", hi - 1, 3},
+		{"spanning the range", lo, hi - lo + 2},
+	}
+	for _, tt := range tests {
+		crafted, _ := NewDDSketch(0.02)
+		crafted.pos = denseStore{offset: tt.offset, counts: make([]uint64, tt.n)}
+		crafted.pos.counts[0] = 1
+		data, _ := crafted.MarshalBinary()
+		var decoded DDSketch
+		if err := decoded.UnmarshalBinary(data); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: expected ErrCorrupt, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestDDSketchEmpty(t *testing.T) {
+	if _, err := NewDDSketch(1); err == nil {
+		t.Errorf("expected error for accuracy 1")
+	}
+	if _, err := NewDDSketch(1e-17); err == nil {
+		t.Errorf("expected error for an accuracy that rounds gamma to 1")
+	}
+	s, _ := NewDDSketch(0.01)
+	if !math.IsNaN(s.Quantile(0.5)) || !math.IsNaN(s.Mean()) {
+		t.Errorf("expected NaN for an empty sketch")
+	}
+	s.Add(math.NaN())
+	if s.Count() != 0 {
+		t.Errorf("NaN must be ignored")
+	}
+}
+
+func TestHistogramErrorBound(t *testing.T) {
+	r := rand.New(rand.NewPCG(13, 17))
+	const digits = 3
+	h, err := NewHistogram(1, 3_600_000_000, digits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xs := make([]int64, 100_000)
+	for i := range xs {
+		// latency-like: mostly fast with a long tail
+		xs[i] = int64(math.Exp(r.NormFloat64()*1.5 + 8))
+		if err := h.Record(xs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Sort(xs)
+
+	for _, q := range testQuantiles {
+		rank := max(1, int(math.Ceil(q*float64(len(xs)))))
+		exact := xs[rank-1]
+		got := h.ValueAtQuantile(q)
+		if got < exact || float64(got-exact) > float64(exact)*math.Pow10(-digits) {
+			t.Errorf("q=%v: value %d not within %d digits of exact %d", q, got, digits, exact)
+		}
+	}
+	if h.Min() != xs[0] || h.Max() != xs[len(xs)-1] {
+		t.Errorf("incorrect extremes, min = %d, max = %d", h.Min(), h.Max())
+	}
+
+	var sum float64
+	for _, x := range xs {
+		sum += float64(x)
+	}
+	if mean := sum / float64(len(xs)); math.Abs(h.Mean()-mean) > mean*math.Pow10(-digits) {
+		t.Errorf("incorrect mean, expected = %v, got = %v", mean, h.Mean())
+	}
+}
+
+func TestHistogramMergeAndSerialize(t *testing.T) {
+	a, _ := NewHistogram(1, 1_000_000, 3)
+	b, _ := NewHistogram(1, 1_000_000, 3)
+	all, _ := NewHistogram(1, 1_000_000, 3)
+	for v := int64(1); v <= 10_000; v++ {
+		if v%2 == 0 {
+			a.Record(v * 7)
+		} else {
+			b.Record(v * 7)
+		}
+		all.Record(v * 7)
+	}
+
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shipped Histogram
+	if err := shipped.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Merge(&shipped); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range testQuantiles {
+		if got, expected := a.ValueAtQuantile(q), all.ValueAtQuantile(q); got != expected {
+			t.Errorf("q=%v: merged histogram differs, expected = %d, got = %d", q, expected, got)
+		}
+	}
+	if a.Count() != all.Count() || a.Min() != all.Min() || a.Max() != all.Max() {
+		t.Errorf("incorrect merged count or extremes")
+	}
+
+	// merging into a coarser histogram keeps its precision
+	coarse, _ := NewHistogram(1, 1_000_000, 2)
+	if err := coarse.Merge(all); err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := coarse.ValueAtQuantile(0.5), all.ValueAtQuantile(0.5); math.Abs(float64(got-expected)) > float64(expected)*0.01 {
+		t.Errorf("coarse median too far off, expected = %d, got = %d", expected, got)
+	}
+
+	small, _ := NewHistogram(1, 1000, 3)
+	if err := small.Merge(all); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	if err := small.Record(1001); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("expected ErrOutOfRange, got %v", err)
+	}
+	if err := shipped.UnmarshalBinary(append(data[:len(data):len(data)], 0x00, 0x01)); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestHistogramCorruptExtremes(t *testing.T) {
+	h, _ := NewHistogram(1, 1_000_000, 3)
+	h.Record(100)
+	h.Record(5000)
+	lo, hi := h.countsIndex(100), h.countsIndex(5000)
+
+	// a frame of h with the given extremes and (gap, count) pairs
+	frame := func(minVal, maxVal uint64, pairs ...uint64) []byte {
+		buf := []byte{histogramVersion}
+		for _, x := range append([]uint64{1, 1_000_000, 3, minVal, maxVal}, pairs...) {
+			buf = gbytes.AppendUvarint(buf, x)
+		}
+		return buf
+	}
+	buckets := []uint64{uint64(lo + 1), 1, uint64(hi - lo), 1}
+
+	var decoded Histogram
+	if err := decoded.UnmarshalBinary(frame(100, 5000, buckets...)); err != nil {
+		t.Fatalf("the valid frame must decode: %v", err)
+	}
+	if decoded.Min() != 100 || decoded.Max() != 5000 || decoded.Count() != 2 {
+		t.Errorf("incorrect decoded histogram: min %d, max %d, count %d", decoded.Min(), decoded.Max(), decoded.Count())
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"min above MaxInt64", frame(math.MaxInt64+1, 5000, buckets...)},
+		{"max above MaxInt64", frame(100, math.MaxUint64, buckets...)},
+		{"max above highest", frame(100, 1_000_001, buckets...)},
+		{"min above max", frame(5000, 100, buckets...)},
+		{"min below the first bucket", frame(99, 5000, buckets...)},
+		{"min above the first bucket", frame(200, 5000, buckets...)},
+		{"max below the last bucket", frame(100, 4000, buckets...)},
+		{"max above the last bucket", frame(100, 6000, buckets...)},
+		{"empty bucket", frame(100, 5000, uint64(lo+1), 1, uint64(hi-lo), 0)},
+		{"total overflow", frame(100, 5000, uint64(lo+1), math.MaxUint64, uint64(hi-lo), 2)},
+	}
+	for _, tt := range tests {
+		if err := decoded.UnmarshalBinary(tt.data); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: expected ErrCorrupt, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestHistogramConfig(t *testing.T) {
+	if _, err := NewHistogram(0, 100, 3); err == nil {
+		t.Errorf("expected error for lowest = 0")
+	}
+	if _, err := NewHistogram(1, 100, 6); err == nil {
+		t.Errorf("expected error for 6 significant digits")
+	}
+	if _, err := NewHistogram(10, 19, 3); err == nil {
+		t.Errorf("expected error for highest < 2*lowest")
+	}
+	if _, err := NewHistogram(10, 20, 3); err != nil {
+		t.Errorf("highest = 2*lowest must be accepted: %v", err)
+	}
+	// 2*lowest overflows int64 here
+	if _, err := NewHistogram(math.MaxInt64/2+1, math.MaxInt64, 3); err == nil {
+		t.Errorf("expected error when 2*lowest overflows")
+	}
+	crafted := gbytes.AppendUvarint([]byte{histogramVersion}, math.MaxInt64/2+1)
+	crafted = gbytes.AppendUvarint(crafted, math.MaxInt64)
+	crafted = append(crafted, 3, 0, 0)
+	var decoded Histogram
+	if err := decoded.UnmarshalBinary(crafted); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("expected ErrCorrupt when 2*lowest overflows, got %v", err)
+	}
+
+	h, _ := NewHistogram(1000, 1_000_000_000, 2)
+	h.Record(0)
+	h.Record(999_999_999)
+	if got := h.ValueAtQuantile(1); got != 999_999_999 {
+		t.Errorf("quantile must clamp to the exact maximum, got = %d", got)
+	}
+	if got := h.ValueAtQuantile(0); got > 999 {
+		t.Errorf("values below lowest must land in the first bucket, got = %d", got)
+	}
+}
@@ -0,0 +1,320 @@
+// stats package provides descriptive statistics over numeric slices, such as mean, median, variance, percentiles and correlation.
+package stats
+
+import (
+	"math"
+	"slices"
+
+	"github.com/AntonyChR/go-utils/assert"
+	gmath "github.com/AntonyChR/go-utils/math"
+)
+
+// All functions accept any slice of a gmath.Number type, including vec.Vec.
+// Functions that need at least one element return NaN for empty input,
+// and functions that take two samples panic if their lengths differ.
+
+const errSameLength = "Samples must have the same length"
+
+func toFloats[T gmath.Number](xs []T) []float64 {
+	c := make([]float64, len(xs))
+	for i, x := range xs {
+		c[i] = float64(x)
+	}
+	return c
+}
+
+func sortedFloats[T gmath.Number](xs []T) []float64 {
+	c := toFloats(xs)
+	slices.Sort(c)
+	return c
+}
+
+// Sum returns the sum of the elements of xs.
+func Sum[T gmath.Number](xs []T) float64 {
+	acc := 0.0
+	for _, x := range xs {
+		acc += float64(x)
+	}
+	return acc
+}
+
+// Mean returns the arithmetic mean of xs, or NaN if xs is empty.
+func Mean[T gmath.Number](xs []T) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	return Sum(xs) / float64(len(xs))
+}
+
+// Median returns the middle value of xs, averaging the two middle values
+// when the length is even. It returns NaN if xs is empty.
+func Median[T gmath.Number](xs []T) float64 {
+	return Quantile(xs, 0.5, Linear)
+}
+
+// Mode returns the most frequent values of xs in ascending order. Every value
+// that reaches the highest frequency is returned, so a sample where all values
+// are distinct returns all of them. It returns nil if xs is empty.
+func Mode[T gmath.Number](xs []T) []T {
+	counts := make(map[T]int, len(xs))
+	best := 0
+	for _, x := range xs {
+		counts[x]++
+		best = max(best, counts[x])
+	}
+
+	var modes []T
+	for x, c := range counts {
+		if c == best {
+			modes = append(modes, x)
+		}
+	}
+	slices.Sort(modes)
+	return modes
+}
+
+// Variance returns the population variance of xs, dividing by n.
+// It returns NaN if xs is empty.
+func Variance[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.Variance()
+}
+
+// SampleVariance returns the unbiased sample variance of xs, dividing by n-1.
+// It returns NaN if xs has fewer than two elements.
+func SampleVariance[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.SampleVariance()
+}
+
+// StdDev returns the population standard deviation of xs.
+func StdDev[T gmath.Number](xs []T) float64 {
+	return math.Sqrt(Variance(xs))
+}
+
+// SampleStdDev returns the sample standard deviation of xs.
+func SampleStdDev[T gmath.Number](xs []T) float64 {
+	return math.Sqrt(SampleVariance(xs))
+}
+
+// Skewness returns the population skewness g1 of xs. It returns NaN if xs is
+// empty or has zero variance.
+func Skewness[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.Skewness()
+}
+
+// SampleSkewness returns the adjusted Fisher-Pearson skewness G1 of xs, as
+// reported by spreadsheets. It returns NaN for fewer than three elements.
+func SampleSkewness[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.SampleSkewness()
+}
+
+// Kurtosis returns the population excess kurtosis g2 of xs; a normal
+// distribution has an excess kurtosis of 0. It returns NaN if xs is empty or
+// has zero variance.
+func Kurtosis[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.Kurtosis()
+}
+
+// SampleKurtosis returns the bias-corrected excess kurtosis G2 of xs, as
+// reported by spreadsheets. It returns NaN for fewer than four elements.
+func SampleKurtosis[T gmath.Number](xs []T) float64 {
+	w := welfordOf(xs)
+	return w.SampleKurtosis()
+}
+
+func welfordOf[T gmath.Number](xs []T) Welford {
+	var w Welford
+	for _, x := range xs {
+		w.Add(float64(x))
+	}
+	return w
+}
+
+// QuantileMethod selects how Quantile interpolates between two order
+// statistics. The names follow NumPy; the R type is given for reference.
+type QuantileMethod int
+
+const (
+	// Linear interpolates between the two closest ranks (R type 7). This is
+	// the default of NumPy, R and spreadsheet PERCENTILE.INC.
+	Linear QuantileMethod = iota
+	// Lower returns the closest rank below.
+	Lower
+	// Higher returns the closest rank above.
+	Higher
+	// Nearest returns the closest rank, ties to the even rank.
+	Nearest
+	// Midpoint averages the closest ranks below and above.
+	Midpoint
+	// Hazen interpolates with plotting position (k-0.5)/n (R type 5).
+	Hazen
+	// Weibull interpolates with plotting position k/(n+1) (R type 6), as
+	// spreadsheet PERCENTILE.EXC does.
+	Weibull
+	// MedianUnbiased interpolates with plotting position (k-1/3)/(n+1/3)
+	// (R type 8), recommended by Hyndman and Fan.
+	MedianUnbiased
+)
+
+// Quantile returns the q-quantile of xs using method.
+//
+// Parameters:
+//   - xs: The sample. It is not modified.
+//   - q: The quantile, between 0 and 1.
+//   - method: The interpolation method.
+//
+// Returns:
+//   - The quantile, or NaN if xs is empty or q is outside [0, 1].
+func Quantile[T gmath.Number](xs []T, q float64, method QuantileMethod) float64 {
+	if len(xs) == 0 || q < 0 || q > 1 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	return quantileSorted(sortedFloats(xs), q, method)
+}
+
+// Percentile returns the p-th percentile of xs, with p between 0 and 100.
+// It follows the same conventions as Quantile.
+func Percentile[T gmath.Number](xs []T, p float64, method QuantileMethod) float64 {
+	return Quantile(xs, p/100, method)
+}
+
+// quantileSorted computes a quantile over an already sorted sample.
+func quantileSorted(s []float64, q float64, method QuantileMethod) float64 {
+	n := float64(len(s))
+
+	// h is the zero-based fractional rank of the quantile
+	var h float64
+	switch method {
+	case Hazen:
+		h = n*q - 0.5
+	case Weibull:
+		h = (n+1)*q - 1
+	case MedianUnbiased:
+		h = (n+1.0/3)*q - 2.0/3
+	default:
+		h = (n - 1) * q
+	}
+	h = math.Max(0, math.Min(h, n-1))
+
+	lo, hi := math.Floor(h), math.Ceil(h)
+	switch method {
+	case Lower:
+		return s[int(lo)]
+	case Higher:
+		return s[int(hi)]
+	case Nearest:
+		return s[int(math.RoundToEven(h))]
+	case Midpoint:
+		return (s[int(lo)] + s[int(hi)]) / 2
+	}
+	return s[int(lo)] + (h-lo)*(s[int(hi)]-s[int(lo)])
+}
+
+// IQR returns the interquartile range of xs, the distance between its first
+// and third quartiles using Linear interpolation. It returns NaN if xs is
+// empty.
+func IQR[T gmath.Number](xs []T) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	s := sortedFloats(xs)
+	return quantileSorted(s, 0.75, Linear) - quantileSorted(s, 0.25, Linear)
+}
+
+// Covariance returns the population covariance of xs and ys, dividing by n.
+// It returns NaN if the samples are empty and panics if their lengths differ.
+func Covariance[T gmath.Number](xs, ys []T) float64 {
+	assert.AssertEq(len(xs), len(ys), errSameLength)
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	return comoment(xs, ys) / float64(len(xs))
+}
+
+// SampleCovariance returns the sample covariance of xs and ys, dividing by
+// n-1. It returns NaN for fewer than two pairs and panics if the lengths
+// differ.
+func SampleCovariance[T gmath.Number](xs, ys []T) float64 {
+	assert.AssertEq(len(xs), len(ys), errSameLength)
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	return comoment(xs, ys) / float64(len(xs)-1)
+}
+
+// comoment returns the sum of (x - mean(x)) * (y - mean(y)), updated in a
+// single pass for numerical stability.
+func comoment[T gmath.Number](xs, ys []T) float64 {
+	var mx, my, c float64
+	for i := range xs {
+		n := float64(i + 1)
+		dx := float64(xs[i]) - mx
+		mx += dx / n
+		my += (float64(ys[i]) - my) / n
+		c += dx * (float64(ys[i]) - my)
+	}
+	return c
+}
+
+// Pearson returns the Pearson correlation coefficient of xs and ys, between
+// -1 and 1. It returns NaN if either sample has zero variance or the samples
+// are empty, and panics if their lengths differ.
+func Pearson[T gmath.Number](xs, ys []T) float64 {
+	assert.AssertEq(len(xs), len(ys), errSameLength)
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	sx := Variance(xs)
+	sy := Variance(ys)
+	if sx == 0 || sy == 0 {
+		return math.NaN()
+	}
+	r := Covariance(xs, ys) / math.Sqrt(sx*sy)
+	// keep rounding error from pushing the result out of range
+	return math.Max(-1, math.Min(1, r))
+}
+
+// Spearman returns the Spearman rank correlation coefficient of xs and ys:
+// the Pearson correlation of their ranks, with tied values sharing the
+// average of their ranks.
+func Spearman[T gmath.Number](xs, ys []T) float64 {
+	assert.AssertEq(len(xs), len(ys), errSameLength)
+	return Pearson(Ranks(xs), Ranks(ys))
+}
+
+// Ranks returns the one-based rank of each element of xs. Tied values get
+// the average of the ranks they span, so Ranks([]int{10, 20, 20, 30}) is
+// [1, 2.5, 2.5, 4].
+func Ranks[T gmath.Number](xs []T) []float64 {
+	idx := make([]int, len(xs))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		switch {
+		case xs[a] < xs[b]:
+			return -1
+		case xs[a] > xs[b]:
+			return 1
+		}
+		return 0
+	})
+
+	ranks := make([]float64, len(xs))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && xs[idx[j+1]] == xs[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return ranks
+}
@@ -0,0 +1,214 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/AntonyChR/go-utils/vec"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestDescriptive(t *testing.T) {
+	xs := []int{2, 4, 4, 4, 5, 5, 7, 9}
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Sum", Sum(xs), 40},
+		{"Mean", Mean(xs), 5},
+		{"Median", Median(xs), 4.5},
+		{"Variance", Variance(xs), 4},
+		{"SampleVariance", SampleVariance(xs), 32.0 / 7},
+		{"StdDev", StdDev(xs), 2},
+		{"Skewness", Skewness(xs), 0.65625},
+		{"SampleSkewness", SampleSkewness(xs), 0.8184875533567997},
+		{"Kurtosis", Kurtosis(xs), -0.21875},
+		{"SampleKurtosis", SampleKurtosis(xs), 0.940625},
+		{"IQR", IQR(xs), 1.5},
+	}
+
+	for _, tt := range tests {
+		if !almostEqual(tt.got, tt.expected) {
+			t.Errorf("%s: expected = %v, got = %v", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	var empty []float64
+	for name, v := range map[string]float64{
+		"Mean":           Mean(empty),
+		"Median":         Median(empty),
+		"Variance":       Variance(empty),
+		"SampleVariance": SampleVariance([]float64{1}),
+		"IQR":            IQR(empty),
+		"Covariance":     Covariance(empty, empty),
+	} {
+		if !math.IsNaN(v) {
+			t.Errorf("%s: expected NaN, got = %v", name, v)
+		}
+	}
+	if Mode(empty) != nil {
+		t.Errorf("Mode: expected nil for empty input")
+	}
+}
+
+func TestMode(t *testing.T) {
+	if got := Mode([]int{3, 1, 3, 2, 1}); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("incorrect modes, expected = [1 3], got = %v", got)
+	}
+	if got := Mode([]float64{2.5}); !slices.Equal(got, []float64{2.5}) {
+		t.Errorf("incorrect modes, expected = [2.5], got = %v", got)
+	}
+}
+
+func TestQuantileMethods(t *testing.T) {
+	xs := []float64{4, 1, 3, 2}
+
+	tests := []struct {
+		method   QuantileMethod
+		expected float64
+	}{
+		{Linear, 1.75},
+		{Lower, 1},
+		{Higher, 2},
+		{Nearest, 2},
+		{Midpoint, 1.5},
+		{Hazen, 1.5},
+		{Weibull, 1.25},
+		{MedianUnbiased, 1.0 + 5.0/12},
+	}
+
+	for _, tt := range tests {
+		if got := Quantile(xs, 0.25, tt.method); !almostEqual(got, tt.expected) {
+			t.Errorf("method %d: expected = %v, got = %v", tt.method, tt.expected, got)
+		}
+	}
+
+	if !slices.Equal(xs, []float64{4, 1, 3, 2}) {
+		t.Errorf("Quantile must not modify its input, got = %v", xs)
+	}
+	if got := Percentile([]int{15, 20, 35, 40, 50}, 40, Linear); !almostEqual(got, 29) {
+		t.Errorf("incorrect percentile, expected = 29, got = %v", got)
+	}
+	if got := Quantile(xs, 1, Weibull); got != 4 {
+		t.Errorf("quantile must clamp to the sample range, got = %v", got)
+	}
+	if got := Quantile(xs, 1.5, Linear); !math.IsNaN(got) {
+		t.Errorf("expected NaN for q out of range, got = %v", got)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	ys := []float64{2, 4, 5, 4, 5}
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Covariance", Covariance(xs, ys), 1.2},
+		{"SampleCovariance", SampleCovariance(xs, ys), 1.5},
+		{"Pearson", Pearson(xs, ys), math.Sqrt(0.6)},
+		{"Spearman", Spearman(xs, ys), 0.7378647873726218},
+		{"Spearman monotonic", Spearman(xs, []float64{1, 10, 100, 1000, 10000}), 1},
+		{"Pearson inverse", Pearson(xs, []float64{10, 8, 6, 4, 2}), -1},
+	}
+
+	for _, tt := range tests {
+		if !almostEqual(tt.got, tt.expected) {
+			t.Errorf("%s: expected = %v, got = %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	if got := Pearson(xs, []float64{3, 3, 3, 3, 3}); !math.IsNaN(got) {
+		t.Errorf("expected NaN for constant sample, got = %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for samples of different length")
+		}
+	}()
+	Covariance(xs, ys[:3])
+}
+
+func TestRanks(t *testing.T) {
+	if got := Ranks([]int{10, 20, 20, 30}); !slices.Equal(got, []float64{1, 2.5, 2.5, 4}) {
+		t.Errorf("incorrect ranks, got = %v", got)
+	}
+	if got := Ranks([]int{3, 1, 2}); !slices.Equal(got, []float64{3, 1, 2}) {
+		t.Errorf("incorrect ranks, got = %v", got)
+	}
+}
+
+func TestVec(t *testing.T) {
+	v := vec.Vec{1, 2, 3, 4}
+	if Mean(v) != 2.5 || Median(v) != 2.5 {
+		t.Errorf("incorrect statistics for vec.Vec, mean = %v, median = %v", Mean(v), Median(v))
+	}
+}
+
+func TestWelfordMerge(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	xs := make([]float64, 10_000)
+	for i := range xs {
+		xs[i] = r.ExpFloat64()*3 + 100
+	}
+
+	// accumulate in uneven chunks, as parallel workers would
+	var total Welford
+	for start := 0; start < len(xs); {
+		end := min(start+1+r.IntN(997), len(xs))
+		part := welfordOf(xs[start:end])
+		total.Merge(part)
+		start = end
+	}
+
+	whole := welfordOf(xs)
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Count", float64(total.Count()), float64(len(xs))},
+		{"Mean", total.Mean(), Mean(xs)},
+		{"SampleVariance", total.SampleVariance(), whole.SampleVariance()},
+		{"Skewness", total.Skewness(), whole.Skewness()},
+		{"Kurtosis", total.Kurtosis(), whole.Kurtosis()},
+		{"Min", total.Min(), slices.Min(xs)},
+		{"Max", total.Max(), slices.Max(xs)},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-9*math.Max(1, math.Abs(tt.expected)) {
+			t.Errorf("%s: expected = %v, got = %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	// exponential distribution: skewness 2, excess kurtosis 6
+	if math.Abs(total.Skewness()-2) > 0.3 || math.Abs(total.Kurtosis()-6) > 2 {
+		t.Errorf("unexpected shape, skewness = %v, kurtosis = %v", total.Skewness(), total.Kurtosis())
+	}
+}
+
+func TestWelfordEmpty(t *testing.T) {
+	var w Welford
+	if !math.IsNaN(w.Mean()) || !math.IsNaN(w.Variance()) || !math.IsNaN(w.Min()) {
+		t.Errorf("expected NaN statistics for an empty accumulator")
+	}
+
+	w.Merge(Welford{})
+	w.Add(5)
+	if w.Count() != 1 || w.Mean() != 5 || w.Variance() != 0 {
+		t.Errorf("incorrect single value statistics, mean = %v, variance = %v", w.Mean(), w.Variance())
+	}
+}
@@ -0,0 +1,155 @@
+package stats
+
+import "math"
+
+// Welford is a streaming accumulator of count, mean, variance, skewness,
+// kurtosis, minimum and maximum. It uses Welford's online algorithm extended
+// to higher moments, which stays numerically stable over long streams, and
+// accumulators filled in parallel can be combined with Merge.
+//
+// The zero value is an empty accumulator ready to use.
+//
+// Usage:
+//
+//	var w Welford
+//	for _, x := range samples {
+//	    w.Add(x)
+//	}
+//	fmt.Println(w.Mean(), w.SampleStdDev())
+type Welford struct {
+	n              int64
+	mean           float64
+	m2, m3, m4     float64 // sums of powers of deviations from the mean
+	minVal, maxVal float64
+}
+
+// Add includes x in the accumulator.
+func (w *Welford) Add(x float64) {
+	w.Merge(Welford{n: 1, mean: x, minVal: x, maxVal: x})
+}
+
+// Merge combines the statistics of o into w, as if every value added to o
+// had been added to w. It uses the pairwise update formulas of Chan et al.
+// and Pébay.
+func (w *Welford) Merge(o Welford) {
+	if o.n == 0 {
+		return
+	}
+	if w.n == 0 {
+		*w = o
+		return
+	}
+
+	na, nb := float64(w.n), float64(o.n)
+	n := na + nb
+	d := o.mean - w.mean
+	d2 := d * d
+
+	m4 := w.m4 + o.m4 +
+		d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*o.m2+nb*nb*w.m2)/(n*n) +
+		4*d*(na*o.m3-nb*w.m3)/n
+	m3 := w.m3 + o.m3 +
+		d2*d*na*nb*(na-nb)/(n*n) +
+		3*d*(na*o.m2-nb*w.m2)/n
+	m2 := w.m2 + o.m2 + d2*na*nb/n
+
+	w.n += o.n
+	w.mean += d * nb / n
+	w.m2, w.m3, w.m4 = m2, m3, m4
+	w.minVal = math.Min(w.minVal, o.minVal)
+	w.maxVal = math.Max(w.maxVal, o.maxVal)
+}
+
+// Count returns the number of values added.
+func (w *Welford) Count() int64 {
+	return w.n
+}
+
+// Mean returns the mean of the values added, or NaN if there are none.
+func (w *Welford) Mean() float64 {
+	if w.n == 0 {
+		return math.NaN()
+	}
+	return w.mean
+}
+
+// Min returns the smallest value added, or NaN if there are none.
+func (w *Welford) Min() float64 {
+	if w.n == 0 {
+		return math.NaN()
+	}
+	return w.minVal
+}
+
+// Max returns the largest value added, or NaN if there are none.
+func (w *Welford) Max() float64 {
+	if w.n == 0 {
+		return math.NaN()
+	}
+	return w.maxVal
+}
+
+// Variance returns the population variance, or NaN if no values were added.
+func (w *Welford) Variance() float64 {
+	if w.n == 0 {
+		return math.NaN()
+	}
+	return w.m2 / float64(w.n)
+}
+
+// SampleVariance returns the sample variance, or NaN for fewer than two values.
+func (w *Welford) SampleVariance() float64 {
+	if w.n < 2 {
+		return math.NaN()
+	}
+	return w.m2 / float64(w.n-1)
+}
+
+// StdDev returns the population standard deviation.
+func (w *Welford) StdDev() float64 {
+	return math.Sqrt(w.Variance())
+}
+
+// SampleStdDev returns the sample standard deviation.
+func (w *Welford) SampleStdDev() float64 {
+	return math.Sqrt(w.SampleVariance())
+}
+
+// Skewness returns the population skewness g1, or NaN if no values were
+// added or they have zero variance.
+func (w *Welford) Skewness() float64 {
+	if w.n == 0 || w.m2 == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(float64(w.n)) * w.m3 / math.Pow(w.m2, 1.5)
+}
+
+// SampleSkewness returns the adjusted Fisher-Pearson skewness G1, or NaN for
+// fewer than three values.
+func (w *Welford) SampleSkewness() float64 {
+	if w.n < 3 {
+		return math.NaN()
+	}
+	n := float64(w.n)
+	return w.Skewness() * math.Sqrt(n*(n-1)) / (n - 2)
+}
+
+// Kurtosis returns the population excess kurtosis g2, or NaN if no values
+// were added or they have zero variance.
+func (w *Welford) Kurtosis() float64 {
+	if w.n == 0 || w.m2 == 0 {
+		return math.NaN()
+	}
+	return float64(w.n)*w.m4/(w.m2*w.m2) - 3
+}
+
+// SampleKurtosis returns the bias-corrected excess kurtosis G2, or NaN for
+// fewer than four values.
+func (w *Welford) SampleKurtosis() float64 {
+	if w.n < 4 {
+		return math.NaN()
+	}
+	n := float64(w.n)
+	return ((n+1)*w.Kurtosis() + 6) * (n - 1) / ((n - 2) * (n - 3))
+}
@@ -0,0 +1,271 @@
+package vec
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/AntonyChR/go-utils/assert"
+	gmath "github.com/AntonyChR/go-utils/math"
+)
+
+// The methods in this file are checked at compile time: scalar and vector
+// operands have separate methods. The vector methods panic on a size
+// mismatch like Dot does; each has a Checked variant returning ErrShape
+// instead, for sizes that come from untrusted input.
+//
+// Broadcasting: the Checked variants and Broadcast follow NumPy's rule for
+// one dimension. Two vectors combine if they have the same size or if one of
+// them has exactly one element, which then acts as a scalar applied to every
+// element of the other.
+
+// AddScalar returns v with n added to every element.
+func (v *VecOf[T]) AddScalar(n T) VecOf[T] {
+	return v.Map(func(e T) T { return e + n })
+}
+
+// SubScalar returns v with n subtracted from every element.
+func (v *VecOf[T]) SubScalar(n T) VecOf[T] {
+	return v.Map(func(e T) T { return e - n })
+}
+
+// DivScalar returns v with every element divided by n. For integer element
+// types the division truncates and n = 0 panics.
+func (v *VecOf[T]) DivScalar(n T) VecOf[T] {
+	return v.Map(func(e T) T { return e / n })
+}
+
+// PowScalar returns v with every element raised to the power n.
+func (v *VecOf[T]) PowScalar(n float64) VecOf[T] {
+	integer := isInteger[T]()
+	return v.Map(func(e T) T { return fromFloat[T](math.Pow(float64(e), n), integer) })
+}
+
+// AddVec returns the element-wise sum v + u.
+func (v *VecOf[T]) AddVec(u VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	c := make(VecOf[T], len(u))
+	for i, e := range *v {
+		c[i] = e + u[i]
+	}
+	return c
+}
+
+// SubVec returns the element-wise difference v - u.
+func (v *VecOf[T]) SubVec(u VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	c := make(VecOf[T], len(u))
+	for i, e := range *v {
+		c[i] = e - u[i]
+	}
+	return c
+}
+
+// Mul returns the element-wise product of v and u.
+func (v *VecOf[T]) Mul(u VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	c := make(VecOf[T], len(u))
+	for i, e := range *v {
+		c[i] = e * u[i]
+	}
+	return c
+}
+
+// Hadamard returns the Hadamard (element-wise) product of v and u. It is the
+// same as Mul, named after the linear algebra operation.
+func (v *VecOf[T]) Hadamard(u VecOf[T]) VecOf[T] {
+	return v.Mul(u)
+}
+
+// Div returns the element-wise quotient v / u. For floating-point element
+// types division by zero follows IEEE 754 and yields ±Inf or NaN; for
+// integer types it panics.
+func (v *VecOf[T]) Div(u VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	c := make(VecOf[T], len(u))
+	for i, e := range *v {
+		c[i] = e / u[i]
+	}
+	return c
+}
+
+// Pow returns v with every element raised to the power of the matching
+// element of u.
+func (v *VecOf[T]) Pow(u VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	pow := powOf[T]()
+	c := make(VecOf[T], len(u))
+	for i, e := range *v {
+		c[i] = pow(e, u[i])
+	}
+	return c
+}
+
+// powOf returns math.Pow adapted to T.
+func powOf[T gmath.Number]() func(a, b T) T {
+	integer := isInteger[T]()
+	return func(a, b T) T { return fromFloat[T](math.Pow(float64(a), float64(b)), integer) }
+}
+
+// AddChecked returns v + u with broadcasting.
+//
+// Returns:
+//   - ErrShape if the sizes cannot be broadcast together.
+func (v *VecOf[T]) AddChecked(u VecOf[T]) (VecOf[T], error) {
+	return Apply(*v, u, func(a, b T) T { return a + b })
+}
+
+// SubChecked returns v - u with broadcasting. It follows the same
+// conventions as AddChecked.
+func (v *VecOf[T]) SubChecked(u VecOf[T]) (VecOf[T], error) {
+	return Apply(*v, u, func(a, b T) T { return a - b })
+}
+
+// MulChecked returns the element-wise product of v and u with broadcasting.
+// It follows the same conventions as AddChecked.
+func (v *VecOf[T]) MulChecked(u VecOf[T]) (VecOf[T], error) {
+	return Apply(*v, u, func(a, b T) T { return a * b })
+}
+
+// DivChecked returns the element-wise quotient v / u with broadcasting. It
+// follows the same conventions as AddChecked.
+func (v *VecOf[T]) DivChecked(u VecOf[T]) (VecOf[T], error) {
+	return Apply(*v, u, func(a, b T) T { return a / b })
+}
+
+// PowChecked returns v raised element-wise to the powers in u with
+// broadcasting. It follows the same conventions as AddChecked.
+func (v *VecOf[T]) PowChecked(u VecOf[T]) (VecOf[T], error) {
+	return Apply(*v, u, powOf[T]())
+}
+
+// DotChecked returns the dot product of v and u.
+//
+// Returns:
+//   - ErrShape if v and u have different sizes. Dot products do not
+//     broadcast.
+func (v *VecOf[T]) DotChecked(u VecOf[T]) (T, error) {
+	if len(*v) != len(u) {
+		return 0, fmt.Errorf("%w: cannot take the dot product of sizes %d and %d", ErrShape, len(*v), len(u))
+	}
+	return v.Dot(u), nil
+}
+
+// BroadcastSize returns the size of the result of combining vectors of
+// sizes n and m element-wise.
+//
+// Returns:
+//   - ErrShape if n and m differ and neither is 1.
+func BroadcastSize(n, m int) (int, error) {
+	switch {
+	case n == m:
+		return n, nil
+	case n == 1:
+		return m, nil
+	case m == 1:
+		return n, nil
+	}
+	return 0, fmt.Errorf("%w: sizes %d and %d cannot be broadcast together", ErrShape, n, m)
+}
+
+// Broadcast returns copies of a and b expanded to their common size.
+//
+// Returns:
+//   - ErrShape if the sizes cannot be broadcast together.
+func Broadcast[T gmath.Number](a, b VecOf[T]) (VecOf[T], VecOf[T], error) {
+	n, err := BroadcastSize(len(a), len(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	expand := func(v VecOf[T]) VecOf[T] {
+		c := make(VecOf[T], n)
+		if len(v) == n {
+			copy(c, v)
+		} else {
+			c.Fill(v[0])
+		}
+		return c
+	}
+	return expand(a), expand(b), nil
+}
+
+// Apply combines a and b element-wise with op, broadcasting a one-element
+// vector over the other.
+//
+// Parameters:
+//   - a, b: The operands.
+//   - op: The operation applied to each pair of elements.
+//
+// Returns:
+//   - A new vector with the results.
+//   - ErrShape if the sizes cannot be broadcast together.
+func Apply[T gmath.Number](a, b VecOf[T], op func(a, b T) T) (VecOf[T], error) {
+	n, err := BroadcastSize(len(a), len(b))
+	if err != nil {
+		return nil, err
+	}
+	// stride 0 repeats the single element of a broadcast operand
+	sa, sb := 1, 1
+	if len(a) != n {
+		sa = 0
+	}
+	if len(b) != n {
+		sb = 0
+	}
+	c := make(VecOf[T], n)
+	for i := range n {
+		c[i] = op(a[i*sa], b[i*sb])
+	}
+	return c, nil
+}
+
+// operand converts the argument of Add and Sub to a vector, or to a scalar
+// with isVec = false. It accepts numbers of any integer or float kind,
+// including named ones, and slices of them, which covers every VecOf.
+// Integers are converted to T directly, like a Go conversion, so they keep
+// their precision beyond 2^53; floats are rounded to the nearest integer
+// for an integer T, like Convert. It panics for any other type.
+func operand[T gmath.Number](value any) (u VecOf[T], scalar T, isVec bool) {
+	switch x := value.(type) {
+	case VecOf[T]:
+		return x, 0, true
+	case []T:
+		return x, 0, true
+	case T:
+		return nil, x, false
+	}
+
+	integer := isInteger[T]()
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice && isNumberKind(rv.Type().Elem().Kind()) {
+		u = make(VecOf[T], rv.Len())
+		for i := range u {
+			u[i], _ = numberOf[T](rv.Index(i), integer)
+		}
+		return u, 0, true
+	}
+	if n, ok := numberOf[T](rv, integer); ok {
+		return nil, n, false
+	}
+	panic(fmt.Sprintf("The parameter 'value' must be a number, a slice or an instance of VecOf, got %T", value))
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64 && k != reflect.Uintptr
+}
+
+// numberOf converts a reflected number to T, and reports false if rv is not
+// a number.
+func numberOf[T gmath.Number](rv reflect.Value, integer bool) (T, bool) {
+	if !rv.IsValid() || !isNumberKind(rv.Kind()) {
+		return 0, false
+	}
+	switch {
+	case rv.CanInt():
+		return T(rv.Int()), true
+	case rv.CanUint():
+		return T(rv.Uint()), true
+	default:
+		return fromFloat[T](rv.Float(), integer), true
+	}
+}
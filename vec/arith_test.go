@@ -0,0 +1,131 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestArith(t *testing.T) {
+	v, u := Vec{1, 2, 3}, Vec{2, 4, 0.5}
+	tests := []struct {
+		name     string
+		got      Vec
+		expected Vec
+	}{
+		{"AddScalar", v.AddScalar(1), Vec{2, 3, 4}},
+		{"SubScalar", v.SubScalar(1), Vec{0, 1, 2}},
+		{"DivScalar", v.DivScalar(2), Vec{0.5, 1, 1.5}},
+		{"PowScalar", v.PowScalar(2), Vec{1, 4, 9}},
+		{"AddVec", v.AddVec(u), Vec{3, 6, 3.5}},
+		{"SubVec", v.SubVec(u), Vec{-1, -2, 2.5}},
+		{"Mul", v.Mul(u), Vec{2, 8, 1.5}},
+		{"Hadamard", v.Hadamard(u), Vec{2, 8, 1.5}},
+		{"Div", v.Div(u), Vec{0.5, 0.5, 6}},
+		{"Pow", v.Pow(u), Vec{1, 16, math.Sqrt(3)}},
+		// the any-typed methods accept every numeric type and numeric slices
+		{"Add int", v.Add(2), Vec{3, 4, 5}},
+		{"Add uint8", v.Add(uint8(1)), Vec{2, 3, 4}},
+		{"Add float32", v.Add(float32(0.5)), Vec{1.5, 2.5, 3.5}},
+		{"Add []float64", v.Add([]float64{1, 1, 1}), Vec{2, 3, 4}},
+		{"Sub int64", v.Sub(int64(1)), Vec{0, 1, 2}},
+		{"Sub Vec", v.Sub(u), Vec{-1, -2, 2.5}},
+	}
+	for _, tt := range tests {
+		if !vecNear(tt.got, tt.expected, 1e-15) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	// every slice kind, and integers that float64 cannot hold
+	type celsius float32
+	big := VecOf[int64]{1 << 53}
+	ints := VecOf[int32]{1, 2, 3}
+	untyped := []any{
+		[]float32{1, 1, 1}, []int64{1, 1, 1}, VecOf[int32]{1, 1, 1}, []uint16{1, 1, 1}, []celsius{1, 1, 1},
+	}
+	for _, u := range untyped {
+		if got := v.Add(u); !got.Equals(Vec{2, 3, 4}) {
+			t.Errorf("Add(%T) was incorrect, got: %v", u, got)
+		}
+	}
+	if got := big.Add(int64(1)); got[0] != 1<<53+1 {
+		t.Errorf("Add(int64) lost precision, got: %v", got)
+	}
+	if got := big.Sub(uint64(1<<53 + 1)); got[0] != -1 {
+		t.Errorf("Sub(uint64) lost precision, got: %v", got)
+	}
+	if got := big.Add([]int64{1}); got[0] != 1<<53+1 {
+		t.Errorf("Add([]int64) lost precision, got: %v", got)
+	}
+	if got := ints.Add([]float64{0.4, 0.6, -2.5}); !got.Equals(VecOf[int32]{1, 3, 0}) {
+		t.Errorf("Add([]float64) to integers was incorrect, got: %v", got)
+	}
+
+	for _, bad := range []any{"1", []string{"1"}, nil, []any{1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for a %T operand", bad)
+				}
+			}()
+			v.Add(bad)
+		}()
+	}
+}
+
+func TestArithChecked(t *testing.T) {
+	v := Vec{1, 2, 3}
+	tests := []struct {
+		name     string
+		op       func(Vec) (Vec, error)
+		u        Vec
+		expected Vec
+	}{
+		{"AddChecked", v.AddChecked, Vec{1, 1, 1}, Vec{2, 3, 4}},
+		{"AddChecked broadcast", v.AddChecked, Vec{10}, Vec{11, 12, 13}},
+		{"SubChecked broadcast", v.SubChecked, Vec{1}, Vec{0, 1, 2}},
+		{"MulChecked", v.MulChecked, Vec{2, 0, -1}, Vec{2, 0, -3}},
+		{"DivChecked broadcast", v.DivChecked, Vec{2}, Vec{0.5, 1, 1.5}},
+		{"PowChecked broadcast", v.PowChecked, Vec{2}, Vec{1, 4, 9}},
+	}
+	for _, tt := range tests {
+		got, err := tt.op(tt.u)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !vecNear(got, tt.expected, 1e-15) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, got, tt.expected)
+		}
+	}
+
+	// a one-element receiver broadcasts too
+	one := Vec{2}
+	if got, _ := one.SubChecked(v); !vecNear(got, Vec{1, 0, -1}, 0) {
+		t.Errorf("broadcasting the receiver was incorrect, got: %v", got)
+	}
+
+	if _, err := v.AddChecked(Vec{1, 2}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := v.DotChecked(Vec{1}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if d, err := v.DotChecked(Vec{1, 1, 1}); err != nil || d != 6 {
+		t.Errorf("DotChecked was incorrect, got: %v, %v", d, err)
+	}
+
+	a, b, err := Broadcast(Vec{5}, Vec{1, 2})
+	if err != nil || !vecNear(a, Vec{5, 5}, 0) || !vecNear(b, Vec{1, 2}, 0) {
+		t.Errorf("Broadcast was incorrect, got: %v, %v, %v", a, b, err)
+	}
+	sizes := []struct{ n, m, expected int }{{3, 3, 3}, {1, 4, 4}, {4, 1, 4}, {1, 0, 0}, {0, 0, 0}}
+	for _, s := range sizes {
+		if got, err := BroadcastSize(s.n, s.m); err != nil || got != s.expected {
+			t.Errorf("BroadcastSize(%d, %d) was incorrect, got: %v, %v", s.n, s.m, got, err)
+		}
+	}
+	if _, err := BroadcastSize(2, 3); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
@@ -0,0 +1,394 @@
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// batchChunk is the number of points processed per block by the batch
+// operations, so the per-block buffers of every coordinate stay in the L1
+// cache.
+const batchChunk = 512
+
+// Points is a set of points of the same dimension stored as a struct of
+// arrays: one contiguous slice per coordinate instead of one slice per
+// point. Batch operations on it run over long contiguous float64 slices with
+// unrolled loops, which the compiler can keep in registers and vectorize,
+// and are much faster than calling the Vec methods on each point.
+type Points struct {
+	n      int
+	coords [][]float64 // coords[d][i] is coordinate d of point i
+}
+
+// NewPoints creates a set of n points of the given dimension, all at the
+// origin.
+func NewPoints(dim, n int) *Points {
+	p := &Points{n: n, coords: make([][]float64, dim)}
+	backing := make([]float64, dim*n)
+	for d := range p.coords {
+		p.coords[d] = backing[d*n : (d+1)*n : (d+1)*n]
+	}
+	return p
+}
+
+// PointsFrom creates a set of points from a slice of vectors. An empty
+// slice gives a set of dimension 0; use NewPoints to keep the dimension.
+//
+// Returns:
+//   - ErrShape if the vectors do not all have the same dimension.
+func PointsFrom(vs []Vec) (*Points, error) {
+	if len(vs) == 0 {
+		return NewPoints(0, 0), nil
+	}
+	p := NewPoints(len(vs[0]), len(vs))
+	for i, v := range vs {
+		if len(v) != p.Dim() {
+			return nil, fmt.Errorf("%w: point %d has dimension %d, expected %d", ErrShape, i, len(v), p.Dim())
+		}
+		for d, x := range v {
+			p.coords[d][i] = x
+		}
+	}
+	return p, nil
+}
+
+// Len returns the number of points.
+func (p *Points) Len() int {
+	return p.n
+}
+
+// Dim returns the dimension of the points.
+func (p *Points) Dim() int {
+	return len(p.coords)
+}
+
+// Coord returns the slice holding coordinate d of every point. It is not a
+// copy: modifying it modifies the points.
+func (p *Points) Coord(d int) []float64 {
+	return p.coords[d]
+}
+
+// At returns a copy of point i.
+func (p *Points) At(i int) Vec {
+	v := make(Vec, p.Dim())
+	for d, c := range p.coords {
+		v[d] = c[i]
+	}
+	return v
+}
+
+// Set replaces point i with v. It panics if v does not have the dimension of
+// the points.
+func (p *Points) Set(i int, v Vec) {
+	if len(v) != p.Dim() {
+		panic(fmt.Sprintf("vec: point of dimension %d in a set of dimension %d", len(v), p.Dim()))
+	}
+	for d, c := range p.coords {
+		c[i] = v[d]
+	}
+}
+
+// Vecs returns a copy of the points as a slice of vectors.
+func (p *Points) Vecs() []Vec {
+	vs := make([]Vec, p.n)
+	for i := range vs {
+		vs[i] = p.At(i)
+	}
+	return vs
+}
+
+func (p *Points) checkDim(v Vec) error {
+	if len(v) != p.Dim() {
+		return fmt.Errorf("%w: vector of dimension %d for points of dimension %d", ErrShape, len(v), p.Dim())
+	}
+	return nil
+}
+
+// resize returns dst with length n, reusing its storage if it is large
+// enough.
+func resize[T any](dst []T, n int) []T {
+	if cap(dst) >= n {
+		return dst[:n]
+	}
+	return make([]T, n)
+}
+
+//
+// bulk operations
+//
+
+// Sum returns the sum of all the points.
+func (p *Points) Sum() Vec {
+	s := make(Vec, p.Dim())
+	for d, c := range p.coords {
+		s[d] = sumUnrolled(c)
+	}
+	return s
+}
+
+// Mean returns the centroid of the points, or a vector of NaN if there are
+// none.
+func (p *Points) Mean() Vec {
+	s := p.Sum()
+	for d := range s {
+		s[d] /= float64(p.n)
+	}
+	return s
+}
+
+// Norms writes the Euclidean norm of every point into dst.
+//
+// Parameters:
+//   - dst: The destination; it is reused if its capacity is at least Len(),
+//     and may be nil.
+//
+// Returns:
+//   - dst, resized to Len().
+func (p *Points) Norms(dst []float64) []float64 {
+	dst = resize(dst, p.n)
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		acc := dst[i0:i1]
+		clear(acc)
+		for _, c := range p.coords {
+			sqAddUnrolled(acc, c[i0:i1])
+		}
+		for k, s := range acc {
+			acc[k] = math.Sqrt(s)
+		}
+	}
+	return dst
+}
+
+// Normalize scales every point to length 1 in place. Points at the origin
+// are left unchanged.
+func (p *Points) Normalize() {
+	var buf [batchChunk]float64
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		inv := buf[:i1-i0]
+		clear(inv)
+		for _, c := range p.coords {
+			sqAddUnrolled(inv, c[i0:i1])
+		}
+		for k, s := range inv {
+			if s == 0 {
+				inv[k] = 1
+			} else {
+				inv[k] = 1 / math.Sqrt(s)
+			}
+		}
+		for _, c := range p.coords {
+			mulUnrolled(c[i0:i1], inv)
+		}
+	}
+}
+
+// Dots writes the dot product of every point with q into dst.
+//
+// Parameters:
+//   - q: The vector, of the same dimension as the points.
+//   - dst: The destination; it is reused if its capacity is at least Len(),
+//     and may be nil.
+//
+// Returns:
+//   - dst, resized to Len().
+//   - ErrShape if q does not have the dimension of the points.
+func (p *Points) Dots(q Vec, dst []float64) ([]float64, error) {
+	if err := p.checkDim(q); err != nil {
+		return nil, err
+	}
+	dst = resize(dst, p.n)
+	clear(dst)
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		for d, c := range p.coords {
+			axpyUnrolled(dst[i0:i1], q[d], c[i0:i1])
+		}
+	}
+	return dst, nil
+}
+
+// DistancesTo writes the Euclidean distance from every point to q into dst.
+// It follows the same conventions as Dots.
+func (p *Points) DistancesTo(q Vec, dst []float64) ([]float64, error) {
+	if err := p.checkDim(q); err != nil {
+		return nil, err
+	}
+	dst = resize(dst, p.n)
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		acc := dst[i0:i1]
+		clear(acc)
+		for d, c := range p.coords {
+			sqDiffAddUnrolled(acc, c[i0:i1], q[d])
+		}
+		for k, s := range acc {
+			acc[k] = math.Sqrt(s)
+		}
+	}
+	return dst, nil
+}
+
+// PairwiseDistances returns the matrix of Euclidean distances between the
+// points of p and the points of o: element (i, j) is the distance from point
+// i of p to point j of o. Pass p itself as o for the distances within p.
+//
+// Returns:
+//   - A p.Len() x o.Len() matrix.
+//   - ErrShape if the sets have different dimensions.
+func (p *Points) PairwiseDistances(o *Points) (*Mat, error) {
+	if p.Dim() != o.Dim() {
+		return nil, fmt.Errorf("%w: points of dimension %d and %d", ErrShape, p.Dim(), o.Dim())
+	}
+	m := ZeroMat(p.n, o.n)
+	var buf [batchChunk]float64
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		acc := buf[:i1-i0]
+		for j := range o.n {
+			clear(acc)
+			for d, c := range p.coords {
+				sqDiffAddUnrolled(acc, c[i0:i1], o.coords[d][j])
+			}
+			for k, s := range acc {
+				m.data[(i0+k)*o.n+j] = math.Sqrt(s)
+			}
+		}
+	}
+	return m, nil
+}
+
+// NearestCentroid assigns every point to its closest centroid, the
+// assignment step of k-means.
+//
+// Parameters:
+//   - centroids: The candidate centroids, of the same dimension as p.
+//   - dst: The destination for the indexes; it is reused if its capacity is
+//     at least Len(), and may be nil.
+//
+// Returns:
+//   - dst, resized to Len(), with the index of the nearest centroid of each
+//     point; ties go to the lowest index.
+//   - ErrShape if the sets have different dimensions or there are no
+//     centroids.
+func (p *Points) NearestCentroid(centroids *Points, dst []int) ([]int, error) {
+	if p.Dim() != centroids.Dim() {
+		return nil, fmt.Errorf("%w: points of dimension %d and centroids of dimension %d", ErrShape, p.Dim(), centroids.Dim())
+	}
+	if centroids.n == 0 {
+		return nil, fmt.Errorf("%w: no centroids", ErrShape)
+	}
+	dst = resize(dst, p.n)
+	var best, acc [batchChunk]float64
+	for i0 := 0; i0 < p.n; i0 += batchChunk {
+		i1 := min(i0+batchChunk, p.n)
+		size := i1 - i0
+		labels := dst[i0:i1]
+		for j := range centroids.n {
+			a := acc[:size]
+			clear(a)
+			for d, c := range p.coords {
+				sqDiffAddUnrolled(a, c[i0:i1], centroids.coords[d][j])
+			}
+			if j == 0 {
+				copy(best[:size], a)
+				clear(labels)
+				continue
+			}
+			for k, s := range a {
+				if s < best[k] {
+					best[k], labels[k] = s, j
+				}
+			}
+		}
+	}
+	return dst, nil
+}
+
+//
+// unrolled kernels: each reslices its inputs to the same length first so
+// the compiler can drop the bounds checks inside the loop
+//
+
+func sumUnrolled(xs []float64) float64 {
+	var s0, s1, s2, s3 float64
+	i := 0
+	for ; i+4 <= len(xs); i += 4 {
+		x := xs[i : i+4 : i+4]
+		s0 += x[0]
+		s1 += x[1]
+		s2 += x[2]
+		s3 += x[3]
+	}
+	for ; i < len(xs); i++ {
+		s0 += xs[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// sqAddUnrolled computes acc[i] += xs[i]².
+func sqAddUnrolled(acc, xs []float64) {
+	xs = xs[:len(acc)]
+	i := 0
+	for ; i+4 <= len(acc); i += 4 {
+		a, x := acc[i:i+4:i+4], xs[i:i+4:i+4]
+		a[0] += x[0] * x[0]
+		a[1] += x[1] * x[1]
+		a[2] += x[2] * x[2]
+		a[3] += x[3] * x[3]
+	}
+	for ; i < len(acc); i++ {
+		acc[i] += xs[i] * xs[i]
+	}
+}
+
+// sqDiffAddUnrolled computes acc[i] += (xs[i]-q)².
+func sqDiffAddUnrolled(acc, xs []float64, q float64) {
+	xs = xs[:len(acc)]
+	i := 0
+	for ; i+4 <= len(acc); i += 4 {
+		a, x := acc[i:i+4:i+4], xs[i:i+4:i+4]
+		d0, d1, d2, d3 := x[0]-q, x[1]-q, x[2]-q, x[3]-q
+		a[0] += d0 * d0
+		a[1] += d1 * d1
+		a[2] += d2 * d2
+		a[3] += d3 * d3
+	}
+	for ; i < len(acc); i++ {
+		d := xs[i] - q
+		acc[i] += d * d
+	}
+}
+
+// axpyUnrolled computes acc[i] += a·xs[i].
+func axpyUnrolled(acc []float64, a float64, xs []float64) {
+	xs = xs[:len(acc)]
+	i := 0
+	for ; i+4 <= len(acc); i += 4 {
+		r, x := acc[i:i+4:i+4], xs[i:i+4:i+4]
+		r[0] += a * x[0]
+		r[1] += a * x[1]
+		r[2] += a * x[2]
+		r[3] += a * x[3]
+	}
+	for ; i < len(acc); i++ {
+		acc[i] += a * xs[i]
+	}
+}
+
+// mulUnrolled computes xs[i] *= fs[i].
+func mulUnrolled(xs, fs []float64) {
+	fs = fs[:len(xs)]
+	i := 0
+	for ; i+4 <= len(xs); i += 4 {
+		x, f := xs[i:i+4:i+4], fs[i:i+4:i+4]
+		x[0] *= f[0]
+		x[1] *= f[1]
+		x[2] *= f[2]
+		x[3] *= f[3]
+	}
+	for ; i < len(xs); i++ {
+		xs[i] *= fs[i]
+	}
+}
@@ -0,0 +1,208 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func randomVecs(r *rand.Rand, n, dim int) []Vec {
+	vs := make([]Vec, n)
+	for i := range vs {
+		vs[i] = make(Vec, dim)
+		for d := range dim {
+			vs[i][d] = r.NormFloat64() * 10
+		}
+	}
+	return vs
+}
+
+func TestPoints(t *testing.T) {
+	r := rand.New(rand.NewPCG(4, 2))
+	for _, dim := range []int{1, 2, 3, 5} {
+		// sizes around the chunk and unrolling boundaries
+		for _, n := range []int{0, 1, 3, 4, 7, batchChunk, batchChunk + 5, 2*batchChunk + 13} {
+			vs := randomVecs(r, n, dim)
+			p := NewPoints(dim, n)
+			for i, v := range vs {
+				p.Set(i, v)
+			}
+			q := randomVecs(r, 1, dim)[0]
+
+			dots, err := p.Dots(q, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dists, _ := p.DistancesTo(q, nil)
+			norms := p.Norms(nil)
+			sum := make(Vec, dim)
+			for i, v := range vs {
+				if math.Abs(dots[i]-v.Dot(q)) > 1e-9 {
+					t.Fatalf("dim=%d n=%d: Dots[%d] = %v, want %v", dim, n, i, dots[i], v.Dot(q))
+				}
+				if math.Abs(dists[i]-v.DistanceTo(q)) > 1e-9 {
+					t.Fatalf("dim=%d n=%d: DistancesTo[%d] = %v, want %v", dim, n, i, dists[i], v.DistanceTo(q))
+				}
+				if math.Abs(norms[i]-v.Norm()) > 1e-9 {
+					t.Fatalf("dim=%d n=%d: Norms[%d] = %v, want %v", dim, n, i, norms[i], v.Norm())
+				}
+				sum.AddInPlace(v)
+			}
+			if got := p.Sum(); !got.ApproxEquals(sum, 1e-9) {
+				t.Errorf("dim=%d n=%d: Sum = %v, want %v", dim, n, got, sum)
+			}
+
+			p.Normalize()
+			for i, v := range vs {
+				if got, expected := p.At(i), v.Unit(); !got.ApproxEquals(expected, 1e-12) {
+					t.Fatalf("dim=%d n=%d: Normalize[%d] = %v, want %v", dim, n, i, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestPairwiseAndNearest(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 9))
+	vs := randomVecs(r, batchChunk+37, 3)
+	cs := randomVecs(r, 7, 3)
+	p, _ := PointsFrom(vs)
+	c, _ := PointsFrom(cs)
+
+	m, err := p.PairwiseDistances(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Rows() != len(vs) || m.Cols() != len(cs) {
+		t.Fatalf("incorrect shape %dx%d", m.Rows(), m.Cols())
+	}
+	labels, err := p.NearestCentroid(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vs {
+		best := 0
+		for j, cj := range cs {
+			if d := v.DistanceTo(cj); math.Abs(m.At(i, j)-d) > 1e-9 {
+				t.Fatalf("distance (%d, %d) = %v, want %v", i, j, m.At(i, j), d)
+			}
+			if v.DistanceTo(cj) < v.DistanceTo(cs[best]) {
+				best = j
+			}
+		}
+		if labels[i] != best {
+			t.Errorf("point %d assigned to %d, want %d", i, labels[i], best)
+		}
+	}
+
+	// dst is reused when large enough
+	buf := make([]int, 0, len(vs))
+	if got, _ := p.NearestCentroid(c, buf); &got[0] != &buf[:1][0] {
+		t.Errorf("NearestCentroid did not reuse dst")
+	}
+
+	if _, err := PointsFrom([]Vec{{1, 2}, {1}}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := p.Dots(Vec{1, 2}, nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := p.NearestCentroid(NewPoints(3, 0), nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := p.PairwiseDistances(NewPoints(2, 1)); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+// each pair of benchmarks runs the same work over 100,000 3D points, one
+// with the per-vector methods and one with the batch API
+
+const benchPoints = 100_000
+
+func benchSet(b *testing.B) ([]Vec, *Points, Vec) {
+	r := rand.New(rand.NewPCG(1, 1))
+	vs := randomVecs(r, benchPoints, 3)
+	p, _ := PointsFrom(vs)
+	b.ResetTimer()
+	return vs, p, Vec{1, 2, 3}
+}
+
+func BenchmarkDistancesLoop(b *testing.B) {
+	vs, _, q := benchSet(b)
+	dst := make([]float64, len(vs))
+	for b.Loop() {
+		for i, v := range vs {
+			dst[i] = v.DistanceTo(q)
+		}
+	}
+}
+
+func BenchmarkDistancesBatch(b *testing.B) {
+	_, p, q := benchSet(b)
+	dst := make([]float64, p.Len())
+	for b.Loop() {
+		p.DistancesTo(q, dst)
+	}
+}
+
+func BenchmarkDotsLoop(b *testing.B) {
+	vs, _, q := benchSet(b)
+	dst := make([]float64, len(vs))
+	for b.Loop() {
+		for i, v := range vs {
+			dst[i] = v.Dot(q)
+		}
+	}
+}
+
+func BenchmarkDotsBatch(b *testing.B) {
+	_, p, q := benchSet(b)
+	dst := make([]float64, p.Len())
+	for b.Loop() {
+		p.Dots(q, dst)
+	}
+}
+
+func BenchmarkNormalizeLoop(b *testing.B) {
+	vs, _, _ := benchSet(b)
+	for b.Loop() {
+		for _, v := range vs {
+			v.UnitInPlace()
+		}
+	}
+}
+
+func BenchmarkNormalizeBatch(b *testing.B) {
+	_, p, _ := benchSet(b)
+	for b.Loop() {
+		p.Normalize()
+	}
+}
+
+func BenchmarkNearestCentroidLoop(b *testing.B) {
+	vs, _, _ := benchSet(b)
+	cs := randomVecs(rand.New(rand.NewPCG(2, 2)), 8, 3)
+	labels := make([]int, len(vs))
+	for b.Loop() {
+		for i, v := range vs {
+			best, bestDist := 0, math.Inf(1)
+			for j, c := range cs {
+				if d := v.DistanceTo(c); d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			labels[i] = best
+		}
+	}
+}
+
+func BenchmarkNearestCentroidBatch(b *testing.B) {
+	_, p, _ := benchSet(b)
+	c, _ := PointsFrom(randomVecs(rand.New(rand.NewPCG(2, 2)), 8, 3))
+	labels := make([]int, p.Len())
+	for b.Loop() {
+		p.NearestCentroid(c, labels)
+	}
+}
@@ -0,0 +1,366 @@
+package vec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// ErrClusterCount is returned when a clustering is asked for a number of
+// clusters that the points cannot provide.
+var ErrClusterCount = errors.New("vec: invalid number of clusters")
+
+// Noise is the label DBSCAN gives to points that belong to no cluster.
+const Noise = -1
+
+// KMeansOptions configures KMeans. The zero value, or nil, uses the
+// defaults.
+type KMeansOptions struct {
+	// MaxIter is the maximum number of refinement iterations. Defaults to
+	// 100.
+	MaxIter int
+	// Tol stops the iterations once no centroid moves farther than it.
+	// Defaults to 1e-6; a negative value waits for the centroids to stop
+	// moving at all.
+	Tol float64
+	// Rand is the source for the k-means++ initialization. The same source,
+	// seeded the same way, gives the same clustering. Defaults to a randomly
+	// seeded source.
+	Rand *rand.Rand
+	// Workers is the number of goroutines assigning points to centroids.
+	// Defaults to GOMAXPROCS. The result does not depend on it.
+	Workers int
+}
+
+func (o *KMeansOptions) withDefaults() KMeansOptions {
+	var d KMeansOptions
+	if o != nil {
+		d = *o
+	}
+	if d.MaxIter <= 0 {
+		d.MaxIter = 100
+	}
+	if d.Tol == 0 {
+		d.Tol = 1e-6
+	} else if d.Tol < 0 {
+		d.Tol = 0
+	}
+	if d.Rand == nil {
+		d.Rand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	if d.Workers <= 0 {
+		d.Workers = runtime.GOMAXPROCS(0)
+	}
+	return d
+}
+
+// KMeansResult is the outcome of KMeans.
+type KMeansResult struct {
+	Centroids  []Vec   // The cluster centers
+	Labels     []int   // The index of the centroid of each point
+	Inertia    float64 // The sum of squared distances of the points to their centroids
+	Iterations int     // The number of refinement iterations run
+	Converged  bool    // Whether the centroids settled before MaxIter
+}
+
+// KMeans partitions points into k clusters with Lloyd's algorithm, starting
+// from centroids chosen by k-means++. A cluster that becomes empty is
+// restarted at the point farthest from its centroid.
+//
+// Parameters:
+//   - points: The points, all of the same dimension.
+//   - k: The number of clusters, between 1 and len(points).
+//   - opts: The options; nil uses the defaults.
+//
+// Returns:
+//   - The clustering.
+//   - ErrShape if the points do not all have the same dimension.
+//   - ErrClusterCount if k is out of range.
+func KMeans(points []Vec, k int, opts *KMeansOptions) (*KMeansResult, error) {
+	if k < 1 || k > len(points) {
+		return nil, fmt.Errorf("%w: %d clusters for %d points", ErrClusterCount, k, len(points))
+	}
+	p, err := PointsFrom(points)
+	if err != nil {
+		return nil, err
+	}
+	o := opts.withDefaults()
+
+	res := &KMeansResult{Centroids: kMeansPlusPlus(p, k, o.Rand)}
+	res.Labels = assignParallel(p, res.Centroids, nil, o.Workers)
+	for res.Iterations < o.MaxIter {
+		moved := updateCentroids(p, res.Centroids, res.Labels)
+		res.Iterations++
+		assignParallel(p, res.Centroids, res.Labels, o.Workers)
+		if moved <= o.Tol {
+			res.Converged = true
+			break
+		}
+	}
+	for i, l := range res.Labels {
+		res.Inertia += points[i].SquaredDistanceTo(res.Centroids[l])
+	}
+	return res, nil
+}
+
+// kMeansPlusPlus picks k of the points as initial centroids, each with a
+// probability proportional to its squared distance to the closest centroid
+// picked so far.
+func kMeansPlusPlus(p *Points, k int, r *rand.Rand) []Vec {
+	centroids := []Vec{p.At(r.IntN(p.Len()))}
+	closest := make([]float64, p.Len())
+	for i := range closest {
+		closest[i] = math.Inf(1)
+	}
+	var dist []float64
+	for len(centroids) < k {
+		dist, _ = p.DistancesTo(centroids[len(centroids)-1], dist)
+		total := 0.0
+		for i, d := range dist {
+			closest[i] = min(closest[i], d*d)
+			total += closest[i]
+		}
+		next := 0
+		if total == 0 {
+			// every point is a copy of a centroid
+			next = r.IntN(p.Len())
+		} else {
+			// rounding may leave target >= 0 at the end: next is then the
+			// last point that can be picked
+			target := r.Float64() * total
+			for i, w := range closest {
+				if w > 0 {
+					next = i
+					if target -= w; target < 0 {
+						break
+					}
+				}
+			}
+		}
+		centroids = append(centroids, p.At(next))
+	}
+	return centroids
+}
+
+// assignParallel writes the index of the closest centroid of every point
+// into dst, splitting the points between workers.
+func assignParallel(p *Points, centroids []Vec, dst []int, workers int) []int {
+	// the centroids have the dimension of the points
+	c, _ := PointsFrom(centroids)
+	dst = resize(dst, p.Len())
+	size := max(batchChunk, (p.Len()+workers-1)/workers)
+	var wg sync.WaitGroup
+	for lo := 0; lo < p.Len(); lo += size {
+		hi := min(lo+size, p.Len())
+		part := &Points{n: hi - lo, coords: make([][]float64, p.Dim())}
+		for d, coord := range p.coords {
+			part.coords[d] = coord[lo:hi]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			part.NearestCentroid(c, dst[lo:hi])
+		}()
+	}
+	wg.Wait()
+	return dst
+}
+
+// updateCentroids moves every centroid to the mean of its points and returns
+// the largest distance a centroid moved.
+func updateCentroids(p *Points, centroids []Vec, labels []int) float64 {
+	sums := make([]Vec, len(centroids))
+	for j := range sums {
+		sums[j] = make(Vec, p.Dim())
+	}
+	counts := make([]int, len(centroids))
+	for i, l := range labels {
+		for d, coord := range p.coords {
+			sums[l][d] += coord[i]
+		}
+		counts[l]++
+	}
+
+	moved := 0.0
+	var used []int // the points that restarted empty clusters
+	for j, sum := range sums {
+		if counts[j] == 0 {
+			far := farthestPoint(p, centroids, labels, used)
+			used = append(used, far)
+			sum = p.At(far)
+		} else {
+			sum.ScaleInPlace(1 / float64(counts[j]))
+		}
+		moved = max(moved, centroids[j].DistanceTo(sum))
+		centroids[j] = sum
+	}
+	return moved
+}
+
+// farthestPoint returns the index of the point farthest from its centroid,
+// skipping the points in used.
+func farthestPoint(p *Points, centroids []Vec, labels []int, used []int) int {
+	far, farDist := 0, -1.0
+	for i, l := range labels {
+		if slices.Contains(used, i) {
+			continue
+		}
+		d := 0.0
+		for dim, coord := range p.coords {
+			diff := coord[i] - centroids[l][dim]
+			d += diff * diff
+		}
+		if d > farDist {
+			far, farDist = i, d
+		}
+	}
+	return far
+}
+
+// DBSCAN clusters points by density: points with at least minPts points
+// within distance eps, counting themselves, are core points, and clusters
+// are the sets of points reachable through chains of core points. Points
+// that no chain reaches are labeled Noise. The result is deterministic:
+// clusters are numbered from 0 in the order of their first point.
+//
+// Parameters:
+//   - points: The points, all of the same dimension.
+//   - eps: The neighborhood radius.
+//   - minPts: The number of neighbors that makes a point a core point.
+//   - metric: The distance between points; nil means Euclidean.
+//
+// Returns:
+//   - The cluster of every point, or Noise.
+//   - ErrShape if the points do not all have the same dimension.
+func DBSCAN(points []Vec, eps float64, minPts int, metric Metric) ([]int, error) {
+	labels := make([]int, len(points))
+	if len(points) == 0 {
+		return labels, nil
+	}
+	tree, err := KDTreeFrom(points, metric)
+	if err != nil {
+		return nil, err
+	}
+
+	const unvisited = -2
+	for i := range labels {
+		labels[i] = unvisited
+	}
+	cluster := 0
+	var pending []int
+	for i, p := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		neighbors, _ := tree.Radius(p, eps)
+		if len(neighbors) < minPts {
+			labels[i] = Noise
+			continue
+		}
+		// points are labeled when they are added, so each one is pending at
+		// most once and the pending list never outgrows the points
+		labels[i] = cluster
+		for {
+			for _, n := range neighbors {
+				switch labels[n.Index] {
+				case Noise:
+					// a border point: in the cluster but does not extend it
+					labels[n.Index] = cluster
+				case unvisited:
+					labels[n.Index] = cluster
+					pending = append(pending, n.Index)
+				}
+			}
+			if len(pending) == 0 {
+				break
+			}
+			next := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			// only core points extend the cluster
+			if neighbors, _ = tree.Radius(points[next], eps); len(neighbors) < minPts {
+				neighbors = nil
+			}
+		}
+		cluster++
+	}
+	return labels, nil
+}
+
+// Silhouette returns the mean silhouette coefficient of a clustering, in
+// [-1, 1]: close to 1 when points are much closer to their own cluster than
+// to the nearest other one, close to 0 when clusters overlap. Points labeled
+// Noise, or any negative label, are ignored, and points alone in their
+// cluster score 0. It compares every pair of points.
+//
+// Parameters:
+//   - points: The points, all of the same dimension.
+//   - labels: The cluster of every point.
+//   - metric: The distance between points; nil means Euclidean.
+//
+// Returns:
+//   - The mean silhouette coefficient.
+//   - ErrShape if labels and points have different lengths.
+//   - ErrClusterCount if there are fewer than 2 clusters.
+func Silhouette(points []Vec, labels []int, metric Metric) (float64, error) {
+	if len(labels) != len(points) {
+		return 0, fmt.Errorf("%w: %d labels for %d points", ErrShape, len(labels), len(points))
+	}
+	if metric == nil {
+		metric = Euclidean{}
+	}
+	k := 0
+	for _, l := range labels {
+		k = max(k, l+1)
+	}
+	sizes := make([]int, k)
+	for _, l := range labels {
+		if l >= 0 {
+			sizes[l]++
+		}
+	}
+	if countNonZero(sizes) < 2 {
+		return 0, fmt.Errorf("%w: the silhouette needs at least 2 clusters", ErrClusterCount)
+	}
+
+	total, n := 0.0, 0
+	sums := make([]float64, k)
+	for i, li := range labels {
+		if li < 0 {
+			continue
+		}
+		n++
+		if sizes[li] == 1 {
+			continue
+		}
+		clear(sums)
+		for j, lj := range labels {
+			if lj >= 0 && j != i {
+				sums[lj] += metric.Distance(points[i], points[j])
+			}
+		}
+		a, b := sums[li]/float64(sizes[li]-1), math.Inf(1)
+		for l, s := range sums {
+			if l != li && sizes[l] > 0 {
+				b = min(b, s/float64(sizes[l]))
+			}
+		}
+		if m := max(a, b); m > 0 {
+			total += (b - a) / m
+		}
+	}
+	return total / float64(n), nil
+}
+
+func countNonZero(xs []int) int {
+	n := 0
+	for _, x := range xs {
+		if x != 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,210 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// blobs returns n points around each center, and the index of the center of
+// each point.
+func blobs(r *rand.Rand, centers []Vec, n int, spread float64) ([]Vec, []int) {
+	var points []Vec
+	var truth []int
+	for c, center := range centers {
+		for range n {
+			p := make(Vec, len(center))
+			for d := range p {
+				p[d] = center[d] + r.NormFloat64()*spread
+			}
+			points = append(points, p)
+			truth = append(truth, c)
+		}
+	}
+	return points, truth
+}
+
+// samePartition reports whether two labelings group the points the same way,
+// whatever the numbering of the clusters.
+func samePartition(a, b []int) bool {
+	ab, ba := map[int]int{}, map[int]int{}
+	for i := range a {
+		if x, ok := ab[a[i]]; ok && x != b[i] {
+			return false
+		}
+		if y, ok := ba[b[i]]; ok && y != a[i] {
+			return false
+		}
+		ab[a[i]], ba[b[i]] = b[i], a[i]
+	}
+	return true
+}
+
+func TestKMeans(t *testing.T) {
+	centers := []Vec{{0, 0}, {20, 0}, {0, 20}, {20, 20}}
+	points, truth := blobs(rand.New(rand.NewPCG(1, 2)), centers, 600, 1)
+
+	res, err := KMeans(points, 4, &KMeansOptions{Rand: rand.New(rand.NewPCG(3, 4))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Converged || res.Iterations >= 100 {
+		t.Errorf("did not converge: %d iterations", res.Iterations)
+	}
+	if !samePartition(res.Labels, truth) {
+		t.Errorf("the clusters do not match the blobs")
+	}
+	for _, c := range centers {
+		found := slices.ContainsFunc(res.Centroids, func(v Vec) bool { return v.DistanceTo(c) < 0.2 })
+		if !found {
+			t.Errorf("no centroid near %v: %v", c, res.Centroids)
+		}
+	}
+	inertia := 0.0
+	for i, p := range points {
+		inertia += p.SquaredDistanceTo(res.Centroids[res.Labels[i]])
+	}
+	if math.Abs(inertia-res.Inertia) > 1e-6 {
+		t.Errorf("Inertia was %v, want %v", res.Inertia, inertia)
+	}
+
+	// the same seed gives the same result whatever the parallelism
+	for _, workers := range []int{1, 3, 16} {
+		again, _ := KMeans(points, 4, &KMeansOptions{Rand: rand.New(rand.NewPCG(3, 4)), Workers: workers})
+		if !slices.Equal(again.Labels, res.Labels) || again.Inertia != res.Inertia {
+			t.Errorf("%d workers: the result changed with the same seed", workers)
+		}
+	}
+
+	// the iteration limit is honored
+	limited, _ := KMeans(points, 4, &KMeansOptions{Rand: rand.New(rand.NewPCG(5, 5)), MaxIter: 1, Tol: -1})
+	if limited.Iterations != 1 || limited.Converged {
+		t.Errorf("MaxIter was not honored: %d iterations", limited.Iterations)
+	}
+}
+
+func TestKMeansEdgeCases(t *testing.T) {
+	points := []Vec{{1, 1}, {1, 1}, {1, 1}, {5, 5}}
+	res, err := KMeans(points, 3, &KMeansOptions{Rand: rand.New(rand.NewPCG(1, 1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Inertia != 0 {
+		t.Errorf("duplicated points must have no inertia, got %v", res.Inertia)
+	}
+
+	one, _ := KMeans(points, 1, nil)
+	if !one.Centroids[0].ApproxEquals(Vec{2, 2}, 1e-12) {
+		t.Errorf("a single centroid must be the mean, got %v", one.Centroids[0])
+	}
+
+	for _, k := range []int{0, 5} {
+		if _, err := KMeans(points, k, nil); !errors.Is(err, ErrClusterCount) {
+			t.Errorf("k=%d: expected ErrClusterCount, got %v", k, err)
+		}
+	}
+	if _, err := KMeans([]Vec{{1}, {1, 2}}, 1, nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+
+	// clusters that empty together restart at different points
+	p, _ := PointsFrom([]Vec{{0}, {1}, {2}, {10}})
+	centroids := []Vec{{0}, {100}, {200}}
+	updateCentroids(p, centroids, []int{0, 0, 0, 0})
+	if centroids[1].Equals(centroids[2]) {
+		t.Errorf("empty clusters restarted at the same point: %v", centroids)
+	}
+}
+
+func TestDBSCAN(t *testing.T) {
+	r := rand.New(rand.NewPCG(8, 8))
+	points, truth := blobs(r, []Vec{{0, 0}, {10, 10}, {-10, 10}}, 100, 0.5)
+	outliers := []Vec{{30, 30}, {-30, -30}, {5, -20}}
+	points = append(points, outliers...)
+
+	labels, err := DBSCAN(points, 1.5, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(truth)
+	if !samePartition(labels[:n], truth) {
+		t.Errorf("the clusters do not match the blobs")
+	}
+	if labels[0] != 0 || labels[100] != 1 || labels[200] != 2 {
+		t.Errorf("clusters must be numbered in order: %d %d %d", labels[0], labels[100], labels[200])
+	}
+	for i := n; i < len(points); i++ {
+		if labels[i] != Noise {
+			t.Errorf("outlier %v labeled %d", points[i], labels[i])
+		}
+	}
+
+	// a border point joins the cluster without extending it
+	line := []Vec{{0}, {0.5}, {1}, {1.5}, {2.9}, {4.3}}
+	labels, _ = DBSCAN(line, 1.5, 4, nil)
+	if expected := []int{0, 0, 0, 0, 0, Noise}; !slices.Equal(labels, expected) {
+		t.Errorf("DBSCAN was incorrect, got: %v, want: %v", labels, expected)
+	}
+
+	// a dense cluster where every point is a neighbor of every other one
+	dense := make([]Vec, 1000)
+	for i := range dense {
+		dense[i] = Vec{r.Float64(), r.Float64()}
+	}
+	labels, _ = DBSCAN(dense, 2, 3, nil)
+	if slices.ContainsFunc(labels, func(l int) bool { return l != 0 }) {
+		t.Errorf("the dense points must form a single cluster")
+	}
+
+	if labels, err := DBSCAN(nil, 1, 1, nil); err != nil || len(labels) != 0 {
+		t.Errorf("DBSCAN of no points was %v, %v", labels, err)
+	}
+}
+
+func TestSilhouette(t *testing.T) {
+	r := rand.New(rand.NewPCG(2, 7))
+	points, truth := blobs(r, []Vec{{0, 0}, {50, 0}}, 50, 1)
+	s, err := Silhouette(points, truth, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s < 0.9 {
+		t.Errorf("well separated clusters scored %v", s)
+	}
+
+	mixed := make([]int, len(truth))
+	for i := range mixed {
+		mixed[i] = i % 2
+	}
+	if s, _ := Silhouette(points, mixed, nil); math.Abs(s) > 0.1 {
+		t.Errorf("random labels scored %v", s)
+	}
+
+	// hand-computed: s(0)=(5-1)/5, s(1)=(4-1)/4, s(2)=0 as a singleton
+	line := []Vec{{0}, {1}, {5}}
+	expected := (0.8 + 0.75 + 0) / 3
+	if s, _ := Silhouette(line, []int{0, 0, 1}, nil); math.Abs(s-expected) > 1e-12 {
+		t.Errorf("Silhouette was %v, want %v", s, expected)
+	}
+	// noise is ignored
+	if s, _ := Silhouette(append(line, Vec{100}), []int{0, 0, 1, Noise}, nil); math.Abs(s-expected) > 1e-12 {
+		t.Errorf("Silhouette with noise was %v, want %v", s, expected)
+	}
+
+	if _, err := Silhouette(line, []int{0, 0, 0}, nil); !errors.Is(err, ErrClusterCount) {
+		t.Errorf("expected ErrClusterCount, got %v", err)
+	}
+	if _, err := Silhouette(line, []int{0, 1}, nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func BenchmarkKMeans(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	points := randomVecs(r, 50_000, 3)
+	for b.Loop() {
+		KMeans(points, 8, &KMeansOptions{Rand: rand.New(rand.NewPCG(1, 1)), MaxIter: 10})
+	}
+}
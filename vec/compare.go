@@ -0,0 +1,187 @@
+package vec
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/AntonyChR/go-utils/assert"
+	gmath "github.com/AntonyChR/go-utils/math"
+)
+
+// The approximate comparisons in this file share the same semantics for
+// special values: NaN is never equal to anything, including NaN, so a
+// computation that produced NaN never passes a comparison by accident, and
+// an infinity is only equal to the infinity of the same sign. Vectors of
+// different sizes are never equal.
+
+// parallelTol is the tolerance of IsParallelTo and IsOrthogonalTo, as the
+// distance between unit vectors or the cosine of the angle between them.
+const parallelTol = 1e-9
+
+// ApproxEquals reports whether v and u are element-wise equal within eps,
+// used both as an absolute tolerance, which matters near zero, and as a
+// tolerance relative to the larger magnitude, which matters for large
+// values.
+//
+// Parameters:
+//   - u: The vector to compare with.
+//   - eps: The tolerance, for example 1e-9.
+//
+// Returns:
+//   - true if |v[i]-u[i]| <= eps·max(1, |v[i]|, |u[i]|) for every i.
+func (v *VecOf[T]) ApproxEquals(u VecOf[T], eps float64) bool {
+	return v.ApproxEqualsTol(u, eps, eps)
+}
+
+// ApproxEqualsTol reports whether v and u are element-wise equal within
+// separate absolute and relative tolerances, as math.NearlyEqual does.
+//
+// Parameters:
+//   - u: The vector to compare with.
+//   - absTol: The absolute tolerance.
+//   - relTol: The tolerance relative to the larger magnitude of each pair.
+//
+// Returns:
+//   - true if |v[i]-u[i]| <= max(relTol·max(|v[i]|, |u[i]|), absTol) for
+//     every i.
+func (v *VecOf[T]) ApproxEqualsTol(u VecOf[T], absTol, relTol float64) bool {
+	if len(*v) != len(u) {
+		return false
+	}
+	for i, e := range *v {
+		if !gmath.NearlyEqual(float64(e), float64(u[i]), relTol, absTol) {
+			return false
+		}
+	}
+	return true
+}
+
+// ULPEquals reports whether every element of v is within maxULP units in
+// the last place of the matching element of u, that is, whether at most
+// maxULP representable values of the element type lie between them. Unlike
+// a fixed tolerance it adapts to the magnitude of each element; 0 and -0 are
+// 0 ULP apart. For integer element types it is the absolute difference.
+func (v *VecOf[T]) ULPEquals(u VecOf[T], maxULP uint64) bool {
+	if len(*v) != len(u) {
+		return false
+	}
+	for i, e := range *v {
+		a, b := float64(e), float64(u[i])
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return false
+		}
+		if math.IsInf(a, 0) || math.IsInf(b, 0) {
+			if a != b {
+				return false
+			}
+			continue
+		}
+		if ulpDistanceOf(e, u[i]) > maxULP {
+			return false
+		}
+	}
+	return true
+}
+
+// ulpDistanceOf returns the ULP distance between a and b in the precision
+// of T.
+func ulpDistanceOf[T gmath.Number](a, b T) uint64 {
+	// dispatch on float-ness and size rather than on the dynamic type, so
+	// named ~float32 and ~float64 types are handled too
+	if !isInteger[T]() {
+		if unsafe.Sizeof(a) == 4 {
+			return ulpDistance32(float32(a), float32(b))
+		}
+		return ulpDistance(float64(a), float64(b))
+	}
+	// subtract after widening: a - b in T overflows for narrow signed types,
+	// and through int64 the bits of every integer type survive, so the
+	// difference is exact modulo 2^64
+	if a > b {
+		return uint64(int64(a)) - uint64(int64(b))
+	}
+	return uint64(int64(b)) - uint64(int64(a))
+}
+
+// ulpDistance32 is the float32 version of ulpDistance.
+func ulpDistance32(a, b float32) uint64 {
+	ordered := func(f float32) int64 {
+		i := int64(int32(math.Float32bits(f)))
+		if i < 0 {
+			return math.MinInt32 - i
+		}
+		return i
+	}
+	ia, ib := ordered(a), ordered(b)
+	if ia > ib {
+		return uint64(ia - ib)
+	}
+	return uint64(ib - ia)
+}
+
+// ulpDistance returns the number of representable float64 values between a
+// and b. It maps the bit patterns onto integers ordered like the floats.
+func ulpDistance(a, b float64) uint64 {
+	ordered := func(f float64) int64 {
+		i := int64(math.Float64bits(f))
+		if i < 0 {
+			return math.MinInt64 - i
+		}
+		return i
+	}
+	ia, ib := ordered(a), ordered(b)
+	if ia > ib {
+		return uint64(ia) - uint64(ib)
+	}
+	return uint64(ib) - uint64(ia)
+}
+
+// AngleBetween returns the angle between v and u in radians, in [0, π]. It
+// uses Kahan's formula 2·atan2(|û-v̂|, |û+v̂|), which stays accurate for
+// nearly parallel vectors, where acos of the normalized dot product loses
+// half of the significant digits.
+//
+// Returns:
+//   - The angle, or NaN if either vector is zero.
+func (v *VecOf[T]) AngleBetween(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	if v.Norm() == 0 || u.Norm() == 0 {
+		return math.NaN()
+	}
+	a, b := unitFloat(*v), unitFloat(u)
+	diff, sum := a.SubVec(b), a.AddVec(b)
+	return 2 * math.Atan2(diff.Norm(), sum.Norm())
+}
+
+// IsOrthogonalTo reports whether v and u are perpendicular, within a
+// tolerance of 1e-9 on the cosine of the angle between them. The zero
+// vector is orthogonal to every vector.
+func (v *VecOf[T]) IsOrthogonalTo(u VecOf[T]) bool {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	dot := 0.0
+	for i, e := range *v {
+		dot += float64(e) * float64(u[i])
+	}
+	return math.Abs(dot) <= parallelTol*v.Norm()*u.Norm()
+}
+
+// IsParallelTo reports whether v and u point in the same or in opposite
+// directions, in any dimension. The unit vectors are compared with a
+// tolerance of 1e-9, so the result does not depend on the magnitudes. The
+// zero vector is parallel to every vector.
+func (v *VecOf[T]) IsParallelTo(u VecOf[T]) bool {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	if v.Norm() == 0 || u.Norm() == 0 {
+		return true
+	}
+	a, b := unitFloat(*v), unitFloat(u)
+	diff, sum := a.SubVec(b), a.AddVec(b)
+	return min(diff.Norm(), sum.Norm()) <= parallelTol
+}
+
+// unitFloat returns v scaled to length 1 as a float64 vector, without the
+// rounding Unit applies to integer element types.
+func unitFloat[T gmath.Number](v VecOf[T]) Vec {
+	f := Convert[float64](v)
+	return f.UnitInPlace()
+}
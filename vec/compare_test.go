@@ -0,0 +1,102 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEquals(t *testing.T) {
+	tests := []struct {
+		a, b     Vec
+		expected bool
+	}{
+		{Vec{1, 2, 3}, Vec{1, 2, 3}, true},
+		{Vec{1, 2, 3}, Vec{1, 2, 4}, false},
+		{Vec{1, 2}, Vec{1, 2, 3}, false},
+		{Vec{0}, Vec{math.Copysign(0, -1)}, true},
+		{Vec{math.NaN()}, Vec{math.NaN()}, false},
+		{Vec{}, Vec{}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Equals(tt.b); got != tt.expected {
+			t.Errorf("Equals(%v, %v) was incorrect, got: %v, want: %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestApproxEquals(t *testing.T) {
+	inf, nan := math.Inf(1), math.NaN()
+	next := math.Nextafter(1, 2)
+	tests := []struct {
+		name     string
+		a, b     Vec
+		eps      float64
+		ulp      uint64
+		approx   bool
+		ulpEqual bool
+	}{
+		{"identical", Vec{1, 2}, Vec{1, 2}, 1e-9, 0, true, true},
+		{"rounding error", Vec{0.1 + 0.2}, Vec{0.3}, 1e-9, 1, true, true},
+		{"near zero", Vec{1e-12}, Vec{-1e-12}, 1e-9, 4, true, false},
+		{"large relative", Vec{1e20}, Vec{1e20 + 1e10}, 1e-9, 4, true, false},
+		{"too far", Vec{1, 2}, Vec{1, 2.001}, 1e-9, 1000, false, false},
+		{"one ULP", Vec{1}, Vec{next}, 0, 1, false, true},
+		{"signed zeros", Vec{0}, Vec{math.Copysign(0, -1)}, 0, 0, true, true},
+		{"same infinity", Vec{inf, 1}, Vec{inf, 1}, 1e-9, 0, true, true},
+		{"opposite infinities", Vec{inf}, Vec{-inf}, 1e-9, math.MaxUint64, false, false},
+		{"infinity and max", Vec{inf}, Vec{math.MaxFloat64}, 1e-9, 10, false, false},
+		{"NaN", Vec{nan}, Vec{nan}, 1e-9, math.MaxUint64, false, false},
+		{"sizes differ", Vec{1}, Vec{1, 1}, 1, 10, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.ApproxEquals(tt.b, tt.eps); got != tt.approx {
+			t.Errorf("%s: ApproxEquals was incorrect, got: %v, want: %v", tt.name, got, tt.approx)
+		}
+		if got := tt.a.ULPEquals(tt.b, tt.ulp); got != tt.ulpEqual {
+			t.Errorf("%s: ULPEquals was incorrect, got: %v, want: %v", tt.name, got, tt.ulpEqual)
+		}
+	}
+
+	// separate tolerances: absolute only, then relative only
+	a, b := Vec{1000}, Vec{1000.5}
+	if a.ApproxEqualsTol(b, 0.1, 0) || !a.ApproxEqualsTol(b, 0, 1e-3) {
+		t.Errorf("ApproxEqualsTol does not apply the tolerances separately")
+	}
+	if got := ulpDistance(-math.SmallestNonzeroFloat64, math.SmallestNonzeroFloat64); got != 2 {
+		t.Errorf("ulpDistance across zero was incorrect, got: %v", got)
+	}
+}
+
+func TestAngles(t *testing.T) {
+	tests := []struct {
+		a, b       Vec
+		angle      float64
+		parallel   bool
+		orthogonal bool
+	}{
+		{Vec{1, 0}, Vec{0, 1}, math.Pi / 2, false, true},
+		{Vec{1, 0}, Vec{-3, 0}, math.Pi, true, false},
+		{Vec{2, 2, 2}, Vec{1, 1, 1}, 0, true, false},
+		{Vec{1, 0, 0, 0}, Vec{1, 1, 0, 0}, math.Pi / 4, false, false},
+		{Vec{1, 2, 3, 4, 5}, Vec{-2e6, -4e6, -6e6, -8e6, -10e6}, math.Pi, true, false},
+		{Vec{1, 0, 1, 0}, Vec{0, 1, 0, -1}, math.Pi / 2, false, true},
+		// nearly parallel: acos(dot) would round this angle to 0
+		{Vec{1, 0}, Vec{1, 1e-10}, 1e-10, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.AngleBetween(tt.b); math.Abs(got-tt.angle) > 1e-15*math.Max(1, tt.angle) {
+			t.Errorf("AngleBetween(%v, %v) was incorrect, got: %v, want: %v", tt.a, tt.b, got, tt.angle)
+		}
+		if got := tt.a.IsParallelTo(tt.b); got != tt.parallel {
+			t.Errorf("IsParallelTo(%v, %v) was incorrect, got: %v, want: %v", tt.a, tt.b, got, tt.parallel)
+		}
+		if got := tt.a.IsOrthogonalTo(tt.b); got != tt.orthogonal {
+			t.Errorf("IsOrthogonalTo(%v, %v) was incorrect, got: %v, want: %v", tt.a, tt.b, got, tt.orthogonal)
+		}
+	}
+
+	zero, v := Zero(3), Vec{1, 2, 3}
+	if !math.IsNaN(zero.AngleBetween(v)) || !zero.IsParallelTo(v) || !zero.IsOrthogonalTo(v) {
+		t.Errorf("incorrect results for the zero vector")
+	}
+}
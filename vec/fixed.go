@@ -0,0 +1,215 @@
+package vec
+
+import (
+	"math"
+
+	"github.com/AntonyChR/go-utils/assert"
+)
+
+// Vec2, Vec3 and Vec4 are fixed-size vectors passed by value. They offer the
+// core operations of Vec without heap allocations, for hot loops such as
+// particle simulations. Use the Vec method and the Vec2/Vec3/Vec4 methods of
+// Vec to convert between the two representations.
+type (
+	Vec2 [2]float64
+	Vec3 [3]float64
+	Vec4 [4]float64
+)
+
+//
+// Vec2
+//
+
+// Add returns a + b.
+func (a Vec2) Add(b Vec2) Vec2 {
+	return Vec2{a[0] + b[0], a[1] + b[1]}
+}
+
+// Sub returns a - b.
+func (a Vec2) Sub(b Vec2) Vec2 {
+	return Vec2{a[0] - b[0], a[1] - b[1]}
+}
+
+// Scale returns a multiplied by n.
+func (a Vec2) Scale(n float64) Vec2 {
+	return Vec2{a[0] * n, a[1] * n}
+}
+
+// Dot returns the dot product of a and b.
+func (a Vec2) Dot(b Vec2) float64 {
+	return a[0]*b[0] + a[1]*b[1]
+}
+
+// Norm returns the Euclidean length of a.
+func (a Vec2) Norm() float64 {
+	return math.Sqrt(a.Dot(a))
+}
+
+// Unit returns a scaled to length 1, or the zero vector if a is zero.
+func (a Vec2) Unit() Vec2 {
+	n := a.Norm()
+	if n == 0 {
+		return Vec2{}
+	}
+	return a.Scale(1 / n)
+}
+
+// DistanceTo returns the Euclidean distance between a and b.
+func (a Vec2) DistanceTo(b Vec2) float64 {
+	return a.Sub(b).Norm()
+}
+
+// Cross returns the z component of the cross product of a and b extended
+// to 3D: positive if b is counter-clockwise from a.
+func (a Vec2) Cross(b Vec2) float64 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+// Rot rotates a counter-clockwise by angle radians, like Vec.Rot.
+func (a Vec2) Rot(angle float64) Vec2 {
+	s, c := math.Sincos(angle)
+	return Vec2{a[0]*c - a[1]*s, a[0]*s + a[1]*c}
+}
+
+// Vec returns a as a new Vec.
+func (a Vec2) Vec() Vec {
+	return Vec{a[0], a[1]}
+}
+
+//
+// Vec3
+//
+
+// Add returns a + b.
+func (a Vec3) Add(b Vec3) Vec3 {
+	return Vec3{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+// Sub returns a - b.
+func (a Vec3) Sub(b Vec3) Vec3 {
+	return Vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+// Scale returns a multiplied by n.
+func (a Vec3) Scale(n float64) Vec3 {
+	return Vec3{a[0] * n, a[1] * n, a[2] * n}
+}
+
+// Dot returns the dot product of a and b.
+func (a Vec3) Dot(b Vec3) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// Norm returns the Euclidean length of a.
+func (a Vec3) Norm() float64 {
+	return math.Sqrt(a.Dot(a))
+}
+
+// Unit returns a scaled to length 1, or the zero vector if a is zero.
+func (a Vec3) Unit() Vec3 {
+	n := a.Norm()
+	if n == 0 {
+		return Vec3{}
+	}
+	return a.Scale(1 / n)
+}
+
+// DistanceTo returns the Euclidean distance between a and b.
+func (a Vec3) DistanceTo(b Vec3) float64 {
+	return a.Sub(b).Norm()
+}
+
+// Cross returns the cross product of a and b, like Vec.Prod3d.
+func (a Vec3) Cross(b Vec3) Vec3 {
+	return Vec3{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// RotAroundAxis rotates a around axis by angle radians, like
+// Vec.RotAroundAxis. It returns a unchanged if axis is the zero vector.
+func (a Vec3) RotAroundAxis(axis Vec3, angle float64) Vec3 {
+	k := axis.Unit()
+	if k == (Vec3{}) {
+		return a
+	}
+	s, c := math.Sincos(angle)
+	return a.Scale(c).Add(k.Cross(a).Scale(s)).Add(k.Scale(k.Dot(a) * (1 - c)))
+}
+
+// Vec returns a as a new Vec.
+func (a Vec3) Vec() Vec {
+	return Vec{a[0], a[1], a[2]}
+}
+
+//
+// Vec4
+//
+
+// Add returns a + b.
+func (a Vec4) Add(b Vec4) Vec4 {
+	return Vec4{a[0] + b[0], a[1] + b[1], a[2] + b[2], a[3] + b[3]}
+}
+
+// Sub returns a - b.
+func (a Vec4) Sub(b Vec4) Vec4 {
+	return Vec4{a[0] - b[0], a[1] - b[1], a[2] - b[2], a[3] - b[3]}
+}
+
+// Scale returns a multiplied by n.
+func (a Vec4) Scale(n float64) Vec4 {
+	return Vec4{a[0] * n, a[1] * n, a[2] * n, a[3] * n}
+}
+
+// Dot returns the dot product of a and b.
+func (a Vec4) Dot(b Vec4) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] + a[3]*b[3]
+}
+
+// Norm returns the Euclidean length of a.
+func (a Vec4) Norm() float64 {
+	return math.Sqrt(a.Dot(a))
+}
+
+// Unit returns a scaled to length 1, or the zero vector if a is zero.
+func (a Vec4) Unit() Vec4 {
+	n := a.Norm()
+	if n == 0 {
+		return Vec4{}
+	}
+	return a.Scale(1 / n)
+}
+
+// DistanceTo returns the Euclidean distance between a and b.
+func (a Vec4) DistanceTo(b Vec4) float64 {
+	return a.Sub(b).Norm()
+}
+
+// Vec returns a as a new Vec.
+func (a Vec4) Vec() Vec {
+	return Vec{a[0], a[1], a[2], a[3]}
+}
+
+//
+// conversions from Vec
+//
+
+// Vec2 converts a 2D Vec to a Vec2. It panics if v does not have 2 elements.
+func (v *VecOf[T]) Vec2() Vec2 {
+	assert.AssertEq(v.Len(), 2, ERROR_2D_METHOD)
+	return Vec2{float64((*v)[0]), float64((*v)[1])}
+}
+
+// Vec3 converts a 3D Vec to a Vec3. It panics if v does not have 3 elements.
+func (v *VecOf[T]) Vec3() Vec3 {
+	assert.AssertEq(v.Len(), 3, ERROR_3D_METHOD)
+	return Vec3{float64((*v)[0]), float64((*v)[1]), float64((*v)[2])}
+}
+
+// Vec4 converts a 4D Vec to a Vec4. It panics if v does not have 4 elements.
+func (v *VecOf[T]) Vec4() Vec4 {
+	assert.AssertEq(v.Len(), 4, ERROR_4D_METHOD)
+	return Vec4{float64((*v)[0]), float64((*v)[1]), float64((*v)[2]), float64((*v)[3])}
+}
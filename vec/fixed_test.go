@@ -0,0 +1,140 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVec2(t *testing.T) {
+	a, b := Vec2{3, 4}, Vec2{1, -2}
+	va := a.Vec()
+	tests := []struct {
+		name     string
+		got      Vec
+		expected Vec
+	}{
+		{"Add", a.Add(b).Vec(), Vec{4, 2}},
+		{"Sub", a.Sub(b).Vec(), Vec{2, 6}},
+		{"Scale", a.Scale(2).Vec(), Vec{6, 8}},
+		{"Unit", a.Unit().Vec(), Vec{0.6, 0.8}},
+		{"Unit of zero", Vec2{}.Unit().Vec(), Vec{0, 0}},
+		{"Rot", a.Rot(0.9).Vec(), va.Rot(0.9)},
+	}
+	for _, tt := range tests {
+		if !vecNear(tt.got, tt.expected, 1e-12) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, tt.got, tt.expected)
+		}
+	}
+	if a.Dot(b) != -5 || a.Norm() != 5 || a.DistanceTo(b) != math.Sqrt(40) || a.Cross(b) != -10 {
+		t.Errorf("incorrect scalar results: %v %v %v %v", a.Dot(b), a.Norm(), a.DistanceTo(b), a.Cross(b))
+	}
+}
+
+func TestVec3(t *testing.T) {
+	a, b := Vec3{2, 3, 4}, Vec3{5, 6, 7}
+	va, vb := a.Vec(), b.Vec()
+	tests := []struct {
+		name     string
+		got      Vec
+		expected Vec
+	}{
+		{"Add", a.Add(b).Vec(), Vec{7, 9, 11}},
+		{"Sub", a.Sub(b).Vec(), Vec{-3, -3, -3}},
+		{"Scale", a.Scale(-1).Vec(), Vec{-2, -3, -4}},
+		{"Unit", a.Unit().Vec(), va.Unit()},
+		{"Cross", a.Cross(b).Vec(), va.Prod3d(vb)},
+		{"RotAroundAxis", a.RotAroundAxis(b, 1.3).Vec(), va.RotAroundAxis(vb, 1.3)},
+		{"RotAroundAxis zero", a.RotAroundAxis(Vec3{}, 1.3).Vec(), va},
+	}
+	for _, tt := range tests {
+		if !vecNear(tt.got, tt.expected, 1e-12) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, tt.got, tt.expected)
+		}
+	}
+	if a.Dot(b) != va.Dot(vb) || a.Norm() != va.Norm() || a.DistanceTo(b) != va.DistanceTo(vb) {
+		t.Errorf("scalar results differ from Vec")
+	}
+}
+
+func TestVec4(t *testing.T) {
+	a, b := Vec4{1, 2, 3, 4}, Vec4{4, 3, 2, 1}
+	if got := a.Add(b); got != (Vec4{5, 5, 5, 5}) {
+		t.Errorf("Add was incorrect, got: %v", got)
+	}
+	if got := a.Sub(b).Scale(2); got != (Vec4{-6, -2, 2, 6}) {
+		t.Errorf("Sub or Scale was incorrect, got: %v", got)
+	}
+	if a.Dot(b) != 20 || a.Norm() != math.Sqrt(30) || a.DistanceTo(b) != math.Sqrt(20) {
+		t.Errorf("incorrect scalar results")
+	}
+	if n := a.Unit().Norm(); math.Abs(n-1) > 1e-15 {
+		t.Errorf("Unit was incorrect, got norm %v", n)
+	}
+}
+
+func TestFixedConversions(t *testing.T) {
+	v2, v3, v4 := Vec{1, 2}, Vec{1, 2, 3}, Vec{1, 2, 3, 4}
+	if v2.Vec2() != (Vec2{1, 2}) || v3.Vec3() != (Vec3{1, 2, 3}) || v4.Vec4() != (Vec4{1, 2, 3, 4}) {
+		t.Errorf("conversion from Vec was incorrect")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic converting a 3D Vec to Vec2")
+		}
+	}()
+	v3.Vec2()
+}
+
+// the benchmarks run the same particle update with both representations:
+// position += velocity*dt, then normalize the velocity
+
+func BenchmarkParticleVec(b *testing.B) {
+	pos, vel := Vec{1, 2, 3}, Vec{0.1, 0.2, 0.3}
+	b.ReportAllocs()
+	for b.Loop() {
+		step := vel.Scale(0.01)
+		pos = pos.Add(step)
+		vel = vel.Unit()
+		_ = pos.Dot(vel)
+	}
+}
+
+func BenchmarkParticleVec3(b *testing.B) {
+	pos, vel := Vec3{1, 2, 3}, Vec3{0.1, 0.2, 0.3}
+	b.ReportAllocs()
+	for b.Loop() {
+		pos = pos.Add(vel.Scale(0.01))
+		vel = vel.Unit()
+		_ = pos.Dot(vel)
+	}
+}
+
+func BenchmarkCrossVec(b *testing.B) {
+	u, v := Vec{1, 2, 3}, Vec{4, 5, 6}
+	b.ReportAllocs()
+	for b.Loop() {
+		c := u.Prod3d(v)
+		u = c.Unit()
+	}
+}
+
+func BenchmarkCrossVec3(b *testing.B) {
+	u, v := Vec3{1, 2, 3}, Vec3{4, 5, 6}
+	b.ReportAllocs()
+	for b.Loop() {
+		u = u.Cross(v).Unit()
+	}
+}
+
+func TestFixedZeroAllocs(t *testing.T) {
+	pos, vel := Vec3{1, 2, 3}, Vec3{0.1, 0.2, 0.3}
+	p2 := Vec2{1, 1}
+	allocs := testing.AllocsPerRun(100, func() {
+		pos = pos.Add(vel.Scale(0.01)).RotAroundAxis(vel, 0.1)
+		vel = vel.Cross(pos).Unit()
+		p2 = p2.Rot(0.1).Sub(p2.Unit())
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v per run", allocs)
+	}
+}
@@ -0,0 +1,205 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVecOfInt(t *testing.T) {
+	a, b := VecOf[int]{3, 4, 0}, VecOf[int]{1, -2, 5}
+
+	tests := []struct {
+		name     string
+		got      VecOf[int]
+		expected VecOf[int]
+	}{
+		{"AddVec", a.AddVec(b), VecOf[int]{4, 2, 5}},
+		{"Add int", a.Add(1), VecOf[int]{4, 5, 1}},
+		{"Add float rounds", a.Add(0.6), VecOf[int]{4, 5, 1}},
+		{"Add []int", a.Add([]int{1, 1, 1}), VecOf[int]{4, 5, 1}},
+		{"Scale", a.Scale(-2), VecOf[int]{-6, -8, 0}},
+		{"Div truncates", a.Div(VecOf[int]{2, 3, 1}), VecOf[int]{1, 1, 0}},
+		{"DivScalar truncates", b.DivScalar(2), VecOf[int]{0, -1, 2}},
+		{"PowScalar", b.PowScalar(2), VecOf[int]{1, 4, 25}},
+		{"Prod3d", a.Prod3d(b), VecOf[int]{20, -15, -10}},
+		{"Unit rounds", a.Unit(), VecOf[int]{1, 1, 0}},
+		{"RotAroundAxis rounds", a.RotAroundAxis(VecOf[int]{0, 0, 7}, math.Pi/2), VecOf[int]{-4, 3, 0}},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equals(tt.expected) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	if a.Dot(b) != -5 || a.Norm() != 5 || a.DistanceTo(b) != math.Sqrt(4+36+25) {
+		t.Errorf("incorrect scalar results: %v %v %v", a.Dot(b), a.Norm(), a.DistanceTo(b))
+	}
+	grid := VecOf[int]{10, 0}
+	if got := grid.Rot(math.Pi / 2); !got.Equals(VecOf[int]{0, 10}) {
+		t.Errorf("Rot was incorrect, got: %v", got)
+	}
+	if !grid.IsParallelTo(VecOf[int]{-3, 0}) || !grid.IsOrthogonalTo(VecOf[int]{0, 2}) {
+		t.Errorf("incorrect parallel or orthogonal test")
+	}
+	// integer angles are computed without rounding the unit vectors
+	if got := grid.AngleBetween(VecOf[int]{1, 1}); math.Abs(got-math.Pi/4) > 1e-15 {
+		t.Errorf("AngleBetween was incorrect, got: %v", got)
+	}
+	if !a.ULPEquals(VecOf[int]{4, 3, 1}, 1) || a.ULPEquals(VecOf[int]{5, 4, 0}, 1) {
+		t.Errorf("ULPEquals on integers must compare absolute differences")
+	}
+	// opposite signs overflow a subtraction in the element type
+	i8, i8min := VecOf[int8]{100}, VecOf[int8]{-128}
+	if !i8.ULPEquals(VecOf[int8]{-100}, 200) || i8.ULPEquals(VecOf[int8]{-100}, 199) || !i8min.ULPEquals(VecOf[int8]{127}, 255) {
+		t.Errorf("ULPEquals on int8 must compare absolute differences")
+	}
+	i16 := VecOf[int16]{-30000}
+	if !i16.ULPEquals(VecOf[int16]{30000}, 60000) || i16.ULPEquals(VecOf[int16]{30000}, 59999) {
+		t.Errorf("ULPEquals on int16 must compare absolute differences")
+	}
+	if got := ulpDistanceOf[int64](math.MinInt64, math.MaxInt64); got != math.MaxUint64 {
+		t.Errorf("ulpDistanceOf on the int64 range was incorrect, got: %v", got)
+	}
+	if got := ulpDistanceOf[uint64](math.MaxUint64, 1); got != math.MaxUint64-1 {
+		t.Errorf("ulpDistanceOf on uint64 was incorrect, got: %v", got)
+	}
+
+	dst := make(VecOf[int], 3)
+	if got := AXPY(dst, 2, a, b); !got.Equals(VecOf[int]{7, 6, 5}) {
+		t.Errorf("AXPY was incorrect, got: %v", got)
+	}
+}
+
+func TestVecOfFloat32(t *testing.T) {
+	a := VecOf[float32]{3, 4}
+	if got := a.Unit(); !got.Equals(VecOf[float32]{0.6, 0.8}) {
+		t.Errorf("Unit was incorrect, got: %v", got)
+	}
+	if got := a.Scale(0.5); !got.Equals(VecOf[float32]{1.5, 2}) {
+		t.Errorf("Scale was incorrect, got: %v", got)
+	}
+
+	next := math.Nextafter32(1, 2)
+	one := VecOf[float32]{1}
+	if !one.ULPEquals(VecOf[float32]{next}, 1) || one.ULPEquals(VecOf[float32]{math.Nextafter32(next, 2)}, 1) {
+		t.Errorf("ULPEquals must count float32 ULPs")
+	}
+	if got := ulpDistance32(-math.SmallestNonzeroFloat32, math.SmallestNonzeroFloat32); got != 2 {
+		t.Errorf("ulpDistance32 across zero was incorrect, got: %v", got)
+	}
+}
+
+func TestVecOfNamedFloat(t *testing.T) {
+	// named float types must count ULPs like their underlying type, not
+	// like integers
+	type meters float64
+	type celsius float32
+
+	m := VecOf[meters]{1e10}
+	if next := meters(math.Nextafter(1e10, 2e10)); !m.ULPEquals(VecOf[meters]{next}, 1) {
+		t.Errorf("ULPEquals must count float64 ULPs for a named float64")
+	}
+	if m.ULPEquals(VecOf[meters]{1e10 + 1}, 1) {
+		t.Errorf("ULPEquals must not take the difference of a named float64")
+	}
+
+	c := VecOf[celsius]{1}
+	next := celsius(math.Nextafter32(1, 2))
+	if !c.ULPEquals(VecOf[celsius]{next}, 1) || c.ULPEquals(VecOf[celsius]{celsius(math.Nextafter32(float32(next), 2))}, 1) {
+		t.Errorf("ULPEquals must count float32 ULPs for a named float32")
+	}
+	if c.ULPEquals(VecOf[celsius]{1.5}, 1) {
+		t.Errorf("ULPEquals must not take the difference of a named float32")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	v := Vec{1.4, 1.5, -2.5, 3}
+	if got := Convert[int](v); !got.Equals(VecOf[int]{1, 2, -3, 3}) {
+		t.Errorf("Convert to int was incorrect, got: %v", got)
+	}
+	if got := Convert[float32](v); !got.Equals(VecOf[float32]{1.4, 1.5, -2.5, 3}) {
+		t.Errorf("Convert to float32 was incorrect, got: %v", got)
+	}
+	ints := VecOf[int8]{-1, 2}
+	if got := Convert[float64](ints); !got.Equals(Vec{-1, 2}) {
+		t.Errorf("Convert to float64 was incorrect, got: %v", got)
+	}
+	if got := ints.Vec2(); got != (Vec2{-1, 2}) {
+		t.Errorf("Vec2 was incorrect, got: %v", got)
+	}
+}
+
+// the baselines are the plain, non-generic loops the float64 Vec methods
+// used before VecOf; the generic instantiation must keep up with them
+
+func dotFloat64(a, b []float64) float64 {
+	acc := 0.0
+	for i := range a {
+		acc += a[i] * b[i]
+	}
+	return acc
+}
+
+func addFloat64(a, b []float64) []float64 {
+	c := make([]float64, len(a))
+	for i := range a {
+		c[i] = a[i] + b[i]
+	}
+	return c
+}
+
+var benchSink float64
+
+func BenchmarkDotBaseline(b *testing.B) {
+	x, y := benchVectors(1024)
+	for b.Loop() {
+		benchSink = dotFloat64(x, y)
+	}
+}
+
+func BenchmarkDotVec(b *testing.B) {
+	x, y := benchVectors(1024)
+	for b.Loop() {
+		benchSink = x.Dot(y)
+	}
+}
+
+func BenchmarkDotVecOfFloat32(b *testing.B) {
+	x, y := benchVectors(1024)
+	fx, fy := Convert[float32](x), Convert[float32](y)
+	for b.Loop() {
+		benchSink = float64(fx.Dot(fy))
+	}
+}
+
+func BenchmarkAddBaseline(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		benchSink = addFloat64(x, y)[0]
+	}
+}
+
+func BenchmarkAddVec(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		benchSink = x.AddVec(y)[0]
+	}
+}
+
+func BenchmarkNormVec(b *testing.B) {
+	x, _ := benchVectors(1024)
+	for b.Loop() {
+		benchSink = x.Norm()
+	}
+}
+
+func BenchmarkNormVecOfInt(b *testing.B) {
+	x, _ := benchVectors(1024)
+	ix := Convert[int](x)
+	for b.Loop() {
+		benchSink = ix.Norm()
+	}
+}
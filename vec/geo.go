@@ -0,0 +1,353 @@
+package vec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrConvergence is returned when an iterative method does not settle,
+	// as Vincenty's formula for nearly antipodal points.
+	ErrConvergence = errors.New("vec: no convergence")
+	// ErrGeohash is returned when decoding a malformed geohash.
+	ErrGeohash = errors.New("vec: invalid geohash")
+)
+
+// EarthRadius is the mean radius of the Earth in meters, used by the
+// spherical formulas.
+const EarthRadius = 6371008.8
+
+// The WGS84 ellipsoid, used by Vincenty's formula and the ECEF conversions.
+const (
+	wgs84A  = 6378137.0               // semi-major axis, in meters
+	wgs84F  = 1 / 298.257223563       // flattening
+	wgs84B  = wgs84A * (1 - wgs84F)   // semi-minor axis
+	wgs84E2 = wgs84F * (2 - wgs84F)   // first eccentricity squared
+	wgs84Ep = wgs84E2 / (1 - wgs84E2) // second eccentricity squared
+)
+
+// deg is the number of radians in a degree.
+const deg = math.Pi / 180
+
+// geohashDigits is the base-32 alphabet of geohashes.
+const geohashDigits = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// LatLng is a geographic position in degrees: latitude north of the equator
+// in [-90, 90], longitude east of Greenwich in [-180, 180].
+//
+// Use it instead of Vec{lat, lon}: Vec.DistanceTo measures degrees on a flat
+// plane, which is wrong everywhere but worse near the poles.
+type LatLng struct {
+	Lat, Lng float64
+}
+
+func (p LatLng) radians() (float64, float64) {
+	return p.Lat * deg, p.Lng * deg
+}
+
+// normalizeLng wraps a longitude in degrees into [-180, 180).
+func normalizeLng(lng float64) float64 {
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// String returns p as "lat,lng".
+func (p LatLng) String() string {
+	return fmt.Sprintf("%g,%g", p.Lat, p.Lng)
+}
+
+//
+// spherical formulas
+//
+
+// HaversineDistance returns the great-circle distance in meters between p
+// and q on a sphere of radius EarthRadius. It is within 0.5% of the distance
+// on the ellipsoid; use VincentyDistance when that matters.
+func (p LatLng) HaversineDistance(q LatLng) float64 {
+	lat1, lng1 := p.radians()
+	lat2, lng2 := q.radians()
+	sLat, sLng := math.Sin((lat2-lat1)/2), math.Sin((lng2-lng1)/2)
+	h := sLat*sLat + math.Cos(lat1)*math.Cos(lat2)*sLng*sLng
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// InitialBearing returns the direction in degrees, clockwise from north in
+// [0, 360), in which to leave p to reach q along a great circle. The bearing
+// changes along the way, except along the equator and meridians.
+func (p LatLng) InitialBearing(q LatLng) float64 {
+	lat1, lng1 := p.radians()
+	lat2, lng2 := q.radians()
+	dLng := lng2 - lng1
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(math.Atan2(y, x)/deg+360, 360)
+}
+
+// Destination returns the point reached by traveling dist meters from p
+// along a great circle, leaving in the direction bearing, in degrees
+// clockwise from north.
+func (p LatLng) Destination(bearing, dist float64) LatLng {
+	lat1, lng1 := p.radians()
+	d := dist / EarthRadius
+	sinD, cosD := math.Sincos(d)
+	sinLat1, cosLat1 := math.Sincos(lat1)
+	sinB, cosB := math.Sincos(bearing * deg)
+	lat2 := math.Asin(math.Max(-1, math.Min(1, sinLat1*cosD+cosLat1*sinD*cosB)))
+	lng2 := lng1 + math.Atan2(sinB*sinD*cosLat1, cosD-sinLat1*math.Sin(lat2))
+	return LatLng{Lat: lat2 / deg, Lng: normalizeLng(lng2 / deg)}
+}
+
+// LatLngBox is the area between two parallels and two meridians. When it
+// crosses the antimeridian, SW.Lng is greater than NE.Lng.
+type LatLngBox struct {
+	SW, NE LatLng
+}
+
+// Contains reports whether p lies in b or on its border.
+func (b LatLngBox) Contains(p LatLng) bool {
+	if p.Lat < b.SW.Lat || p.Lat > b.NE.Lat {
+		return false
+	}
+	if b.SW.Lng <= b.NE.Lng {
+		return b.SW.Lng <= p.Lng && p.Lng <= b.NE.Lng
+	}
+	return p.Lng >= b.SW.Lng || p.Lng <= b.NE.Lng
+}
+
+// Center returns the middle of b.
+func (b LatLngBox) Center() LatLng {
+	lng := (b.SW.Lng + b.NE.Lng) / 2
+	if b.SW.Lng > b.NE.Lng {
+		lng = normalizeLng(lng + 180)
+	}
+	return LatLng{Lat: (b.SW.Lat + b.NE.Lat) / 2, Lng: lng}
+}
+
+// BoundingBox returns the smallest box that contains every point within
+// dist meters of p on the sphere, for filtering candidates before computing
+// exact distances. A box that reaches a pole covers all longitudes.
+func (p LatLng) BoundingBox(dist float64) LatLngBox {
+	lat, _ := p.radians()
+	r := dist / EarthRadius
+	minLat, maxLat := lat-r, lat+r
+	if minLat <= -math.Pi/2 || maxLat >= math.Pi/2 {
+		return LatLngBox{
+			SW: LatLng{Lat: math.Max(minLat/deg, -90), Lng: -180},
+			NE: LatLng{Lat: math.Min(maxLat/deg, 90), Lng: 180},
+		}
+	}
+	dLng := math.Asin(math.Sin(r)/math.Cos(lat)) / deg
+	if dLng >= 180 {
+		dLng = 180
+	}
+	box := LatLngBox{
+		SW: LatLng{Lat: minLat / deg, Lng: normalizeLng(p.Lng - dLng)},
+		NE: LatLng{Lat: maxLat / deg, Lng: normalizeLng(p.Lng + dLng)},
+	}
+	if dLng == 180 {
+		box.SW.Lng, box.NE.Lng = -180, 180
+	}
+	return box
+}
+
+//
+// ellipsoidal formulas
+//
+
+// VincentyDistance returns the distance in meters between p and q on the
+// WGS84 ellipsoid with Vincenty's inverse formula, accurate to within a
+// millimeter.
+//
+// Returns:
+//   - The distance.
+//   - ErrConvergence for some nearly antipodal points, where the formula
+//     does not settle; HaversineDistance is then a good approximation.
+func (p LatLng) VincentyDistance(q LatLng) (float64, error) {
+	lat1, lng1 := p.radians()
+	lat2, lng2 := q.radians()
+	L := lng2 - lng1
+	sinU1, cosU1 := math.Sincos(math.Atan((1 - wgs84F) * math.Tan(lat1)))
+	sinU2, cosU2 := math.Sincos(math.Atan((1 - wgs84F) * math.Tan(lat2)))
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cos2Alpha, cos2SigmaM float64
+	for i := 0; ; i++ {
+		if i == 200 {
+			return 0, fmt.Errorf("%w: Vincenty's formula between %v and %v", ErrConvergence, p, q)
+		}
+		sinL, cosL := math.Sincos(lambda)
+		a, b := cosU2*sinL, cosU1*sinU2-sinU1*cosU2*cosL
+		sinSigma = math.Sqrt(a*a + b*b)
+		if sinSigma == 0 {
+			return 0, nil // the same point
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosL
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinL / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0 // both points on the equator
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		C := wgs84F / 16 * cos2Alpha * (4 + wgs84F*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*
+			(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			break
+		}
+	}
+
+	u2 := cos2Alpha * wgs84Ep
+	A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	c2 := cos2SigmaM * cos2SigmaM
+	dSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*c2)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*c2)))
+	return wgs84B * A * (sigma - dSigma), nil
+}
+
+// ECEF returns the Earth-centered, Earth-fixed coordinates in meters of p at
+// altitude alt meters above the WGS84 ellipsoid: x towards latitude and
+// longitude 0, z towards the north pole.
+func (p LatLng) ECEF(alt float64) Vec3 {
+	lat, lng := p.radians()
+	sinLat, cosLat := math.Sincos(lat)
+	sinLng, cosLng := math.Sincos(lng)
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+	return Vec3{
+		(n + alt) * cosLat * cosLng,
+		(n + alt) * cosLat * sinLng,
+		(n*(1-wgs84E2) + alt) * sinLat,
+	}
+}
+
+// LatLngFromECEF converts Earth-centered, Earth-fixed coordinates in meters
+// back to a position and an altitude above the WGS84 ellipsoid, with
+// Bowring's method refined by fixed-point iterations. It is accurate to well
+// under a millimeter from deep underground to beyond geostationary orbit.
+func LatLngFromECEF(v Vec3) (LatLng, float64) {
+	x, y, z := v[0], v[1], v[2]
+	lng := math.Atan2(y, x)
+	p := math.Hypot(x, y)
+
+	// the altitude of a point at latitude lat, stable at the poles unlike
+	// p/cos(lat) - n
+	altitude := func(lat float64) (float64, float64) {
+		sinLat, cosLat := math.Sincos(lat)
+		n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+		return p*cosLat + z*sinLat - wgs84A*wgs84A/n, n
+	}
+
+	// Bowring's estimate from the parametric latitude
+	sinT, cosT := math.Sincos(math.Atan2(z*wgs84A, p*wgs84B))
+	lat := math.Atan2(z+wgs84Ep*wgs84B*sinT*sinT*sinT, p-wgs84E2*wgs84A*cosT*cosT*cosT)
+	for range 2 {
+		alt, n := altitude(lat)
+		lat = math.Atan2(z, p*(1-wgs84E2*n/(n+alt)))
+	}
+	alt, _ := altitude(lat)
+	return LatLng{Lat: lat / deg, Lng: lng / deg}, alt
+}
+
+// enuAxes returns the east, north and up unit vectors at ref in ECEF
+// coordinates.
+func enuAxes(ref LatLng) (Vec3, Vec3, Vec3) {
+	lat, lng := ref.radians()
+	sinLat, cosLat := math.Sincos(lat)
+	sinLng, cosLng := math.Sincos(lng)
+	return Vec3{-sinLng, cosLng, 0},
+		Vec3{-sinLat * cosLng, -sinLat * sinLng, cosLat},
+		Vec3{cosLat * cosLng, cosLat * sinLng, sinLat}
+}
+
+// ECEFToENU converts Earth-centered, Earth-fixed coordinates to local
+// east-north-up coordinates, in meters, around a reference point.
+//
+// Parameters:
+//   - v: The ECEF coordinates.
+//   - ref: The origin of the local frame.
+//   - refAlt: The altitude of the origin above the WGS84 ellipsoid.
+func ECEFToENU(v Vec3, ref LatLng, refAlt float64) Vec3 {
+	d := v.Sub(ref.ECEF(refAlt))
+	e, n, u := enuAxes(ref)
+	return Vec3{d.Dot(e), d.Dot(n), d.Dot(u)}
+}
+
+// ENUToECEF is the inverse of ECEFToENU.
+func ENUToECEF(enu Vec3, ref LatLng, refAlt float64) Vec3 {
+	e, n, u := enuAxes(ref)
+	return ref.ECEF(refAlt).Add(e.Scale(enu[0])).Add(n.Scale(enu[1])).Add(u.Scale(enu[2]))
+}
+
+//
+// geohash
+//
+
+// Geohash encodes p as a geohash of the given number of characters, between
+// 1 and 12: each one narrows the cell, from about 5000 km for 1 character to
+// about 4 cm for 12. Nearby points usually share a prefix.
+func (p LatLng) Geohash(precision int) string {
+	if precision < 1 || precision > 12 {
+		panic(fmt.Sprintf("vec: geohash precision must be in [1, 12], got %d", precision))
+	}
+	lat, lng := [2]float64{-90, 90}, [2]float64{-180, 180}
+	var sb strings.Builder
+	bit, ch, even := 0, 0, true // even bits refine the longitude
+	for sb.Len() < precision {
+		r, x := &lat, p.Lat
+		if even {
+			r, x = &lng, p.Lng
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if x >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			sb.WriteByte(geohashDigits[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// DecodeGeohash returns the cell of a geohash. Use its Center for a single
+// position. Uppercase letters are accepted.
+//
+// Returns:
+//   - The cell.
+//   - ErrGeohash if hash is empty or has characters outside the geohash
+//     alphabet.
+func DecodeGeohash(hash string) (LatLngBox, error) {
+	if hash == "" {
+		return LatLngBox{}, fmt.Errorf("%w: empty", ErrGeohash)
+	}
+	lat, lng := [2]float64{-90, 90}, [2]float64{-180, 180}
+	even := true
+	for i, c := range strings.ToLower(hash) {
+		v := strings.IndexRune(geohashDigits, c)
+		if v < 0 {
+			return LatLngBox{}, fmt.Errorf("%w: %q at position %d", ErrGeohash, c, i)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			r := &lat
+			if even {
+				r = &lng
+			}
+			mid := (r[0] + r[1]) / 2
+			if v&mask != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return LatLngBox{SW: LatLng{Lat: lat[0], Lng: lng[0]}, NE: LatLng{Lat: lat[1], Lng: lng[1]}}, nil
+}
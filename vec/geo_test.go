@@ -0,0 +1,234 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// dms converts degrees, minutes and seconds to degrees.
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+	return d + m/60 + s/3600
+}
+
+func TestVincentyDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		p, q     LatLng
+		expected float64
+		tol      float64
+	}{
+		// Vincenty's direct and inverse example (Geoscience Australia)
+		{
+			"Flinders Peak to Buninyong",
+			LatLng{dms(-37, 57, 3.72030), dms(144, 25, 29.52440)},
+			LatLng{dms(-37, 39, 10.15610), dms(143, 55, 35.38390)},
+			54972.271, 1e-3,
+		},
+		// a degree of longitude along the equator is a·π/180
+		{"equator", LatLng{0, 0}, LatLng{0, 1}, 6378137 * math.Pi / 180, 1e-6},
+		// the quarter meridian of WGS84
+		{"quarter meridian", LatLng{0, 0}, LatLng{90, 0}, 10001965.729, 1e-3},
+		{"same point", LatLng{12, 34}, LatLng{12, 34}, 0, 0},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.VincentyDistance(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if math.Abs(got-tt.expected) > tt.tol {
+			t.Errorf("%s: VincentyDistance was %.4f, want %.4f", tt.name, got, tt.expected)
+		}
+	}
+
+	// nearly antipodal points where the iteration does not settle
+	if _, err := (LatLng{0, 0}).VincentyDistance(LatLng{0.5, 179.7}); !errors.Is(err, ErrConvergence) {
+		t.Errorf("expected ErrConvergence, got %v", err)
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	// Nashville to Los Angeles, 2887.2599506071106 km on a sphere of radius
+	// 6372.8 km (Rosetta Code)
+	bna, lax := LatLng{36.12, -86.67}, LatLng{33.94, -118.40}
+	expected := 2887259.9506071106 * EarthRadius / 6372800
+	if got := bna.HaversineDistance(lax); math.Abs(got-expected) > 1e-6 {
+		t.Errorf("HaversineDistance was %v, want %v", got, expected)
+	}
+	if got := lax.HaversineDistance(bna); math.Abs(got-expected) > 1e-6 {
+		t.Errorf("HaversineDistance is not symmetric: %v", got)
+	}
+	// within 0.5% of the ellipsoidal distance
+	v, _ := bna.VincentyDistance(lax)
+	if math.Abs(v-expected)/v > 0.005 {
+		t.Errorf("haversine and Vincenty differ too much: %v and %v", expected, v)
+	}
+}
+
+func TestBearingAndDestination(t *testing.T) {
+	bearings := []struct {
+		q        LatLng
+		expected float64
+	}{
+		{LatLng{10, 0}, 0},
+		{LatLng{0, 10}, 90},
+		{LatLng{-10, 0}, 180},
+		{LatLng{0, -10}, 270},
+	}
+	for _, tt := range bearings {
+		if got := (LatLng{}).InitialBearing(tt.q); math.Abs(got-tt.expected) > 1e-12 {
+			t.Errorf("InitialBearing to %v was %v, want %v", tt.q, got, tt.expected)
+		}
+	}
+
+	// Movable Type Scripts example: 124.8 km on bearing 096°01'18" from
+	// 53°19'14"N 001°43'47"W reaches 53°11'18"N 000°08'00"E
+	start := LatLng{dms(53, 19, 14), -dms(1, 43, 47)}
+	got := start.Destination(dms(96, 1, 18), 124800)
+	want := LatLng{dms(53, 11, 18), dms(0, 8, 0)}
+	if math.Abs(got.Lat-want.Lat) > 1.0/3600 || math.Abs(got.Lng-want.Lng) > 1.0/3600 {
+		t.Errorf("Destination was %v, want %v", got, want)
+	}
+
+	// Destination undoes InitialBearing and HaversineDistance
+	r := rand.New(rand.NewPCG(6, 6))
+	for range 100 {
+		p := LatLng{r.Float64()*170 - 85, r.Float64()*360 - 180}
+		q := LatLng{r.Float64()*170 - 85, r.Float64()*360 - 180}
+		d := p.Destination(p.InitialBearing(q), p.HaversineDistance(q))
+		if d.HaversineDistance(q) > 1e-3 {
+			t.Fatalf("Destination from %v missed %v: %v", p, q, d)
+		}
+	}
+
+	// across the antimeridian
+	if got := (LatLng{0, 179.5}).Destination(90, 111195); math.Abs(got.Lng+179.5) > 1e-3 {
+		t.Errorf("Destination across the antimeridian was %v", got)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name string
+		p    LatLng
+		dist float64
+	}{
+		{"mid latitudes", LatLng{45, 10}, 50_000},
+		{"antimeridian", LatLng{-20, 179.9}, 100_000},
+		{"near a pole", LatLng{89.5, 0}, 100_000},
+	}
+	for _, tt := range tests {
+		box := tt.p.BoundingBox(tt.dist)
+		for b := 0.0; b < 360; b += 5 {
+			for _, f := range []float64{0.5, 0.999} {
+				q := tt.p.Destination(b, tt.dist*f)
+				if !box.Contains(q) {
+					t.Fatalf("%s: %v at bearing %v is outside %v", tt.name, q, b, box)
+				}
+			}
+		}
+		// the box is tight along the meridian
+		if north := tt.p.Destination(0, tt.dist*1.01); tt.name != "near a pole" && box.Contains(north) {
+			t.Errorf("%s: %v is inside %v", tt.name, north, box)
+		}
+	}
+
+	anti := LatLng{-20, 179.9}.BoundingBox(100_000)
+	if anti.SW.Lng <= anti.NE.Lng || !anti.Contains(LatLng{-20, -179.9}) || anti.Contains(LatLng{-20, 0}) {
+		t.Errorf("the antimeridian box is incorrect: %v", anti)
+	}
+	if c := anti.Center(); math.Abs(c.Lat+20) > 1e-9 || math.Abs(c.Lng-179.9) > 1e-9 {
+		t.Errorf("Center was %v", c)
+	}
+	if polar := (LatLng{89.5, 0}).BoundingBox(100_000); polar.SW.Lng != -180 || polar.NE.Lng != 180 || polar.NE.Lat != 90 {
+		t.Errorf("the polar box is incorrect: %v", polar)
+	}
+}
+
+func TestECEF(t *testing.T) {
+	tests := []struct {
+		p        LatLng
+		alt      float64
+		expected Vec3
+	}{
+		{LatLng{0, 0}, 0, Vec3{6378137, 0, 0}},
+		{LatLng{0, 90}, 100, Vec3{0, 6378237, 0}},
+		{LatLng{90, 0}, 0, Vec3{0, 0, 6356752.314245179}},
+		{LatLng{-90, 0}, 10, Vec3{0, 0, -6356762.314245179}},
+	}
+	for _, tt := range tests {
+		if got := tt.p.ECEF(tt.alt); got.DistanceTo(tt.expected) > 1e-6 {
+			t.Errorf("ECEF(%v, %v) was %v, want %v", tt.p, tt.alt, got, tt.expected)
+		}
+	}
+
+	r := rand.New(rand.NewPCG(2, 2))
+	for range 1000 {
+		p := LatLng{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		alt := r.Float64()*4e7 - 1e4
+		back, backAlt := LatLngFromECEF(p.ECEF(alt))
+		if math.Abs(back.Lat-p.Lat) > 1e-9 || math.Abs(back.Lng-p.Lng) > 1e-9 || math.Abs(backAlt-alt) > 1e-4 {
+			t.Fatalf("LatLngFromECEF(%v, %v) was %v, %v", p, alt, back, backAlt)
+		}
+	}
+}
+
+func TestENU(t *testing.T) {
+	ref := LatLng{48.8584, 2.2945}
+	up := ECEFToENU(ref.ECEF(330), ref, 0)
+	if up.DistanceTo(Vec3{0, 0, 330}) > 1e-6 {
+		t.Errorf("a point above the reference was %v", up)
+	}
+	// a short step north is mostly along the north axis
+	north := ECEFToENU(LatLng{ref.Lat + 0.001, ref.Lng}.ECEF(0), ref, 0)
+	if math.Abs(north[0]) > 1e-6 || north[1] < 111 || north[1] > 112 || math.Abs(north[2]) > 0.01 {
+		t.Errorf("a point to the north was %v", north)
+	}
+	for _, enu := range []Vec3{{1, 2, 3}, {-1000, 5000, -20}} {
+		if got := ECEFToENU(ENUToECEF(enu, ref, 50), ref, 50); got.DistanceTo(enu) > 1e-6 {
+			t.Errorf("ENU round trip of %v was %v", enu, got)
+		}
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	// examples from the geohash article on Wikipedia
+	tests := []struct {
+		p    LatLng
+		hash string
+	}{
+		{LatLng{42.6, -5.6}, "ezs42"},
+		{LatLng{57.64911, 10.40744}, "u4pruydqqvj"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Geohash(len(tt.hash)); got != tt.hash {
+			t.Errorf("Geohash(%v) was %q, want %q", tt.p, got, tt.hash)
+		}
+		cell, err := DecodeGeohash(tt.hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cell.Contains(tt.p) {
+			t.Errorf("the cell %v of %q does not contain %v", cell, tt.hash, tt.p)
+		}
+	}
+
+	cell, _ := DecodeGeohash("EZS42")
+	if c := cell.Center(); math.Abs(c.Lat-42.605) > 0.001 || math.Abs(c.Lng+5.603) > 0.001 {
+		t.Errorf("the center of ezs42 was %v", c)
+	}
+	// longitude gets the first of the 5 bits of each character
+	if h, w := cell.NE.Lat-cell.SW.Lat, cell.NE.Lng-cell.SW.Lng; h != 180.0/(1<<12) || w != 360.0/(1<<13) {
+		t.Errorf("the cell of a 5 character geohash was %v x %v", h, w)
+	}
+
+	for _, bad := range []string{"", "ezs4a", "u4pr i"} {
+		if _, err := DecodeGeohash(bad); !errors.Is(err, ErrGeohash) {
+			t.Errorf("DecodeGeohash(%q): expected ErrGeohash, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+package vec
+
+import (
+	"cmp"
+	"math"
+	"math/big"
+	"slices"
+)
+
+//
+// orientation predicate
+//
+
+// orientErrBound bounds the rounding error of the floating-point evaluation
+// of Orient2D, relative to the magnitude of its terms (Shewchuk, "Adaptive
+// Precision Floating-Point Arithmetic and Fast Robust Geometric Predicates").
+const orientErrBound = (3 + 16*0x1p-53) * 0x1p-53
+
+// orientTiny is the magnitude below which the products of Orient2D, and
+// their error bound, may have lost precision to underflow.
+const orientTiny = 0x1p-969
+
+// Orient2D returns a value that is positive if a, b and c turn
+// counter-clockwise, negative if they turn clockwise and zero if they are
+// collinear. Its magnitude is twice the area of the triangle abc.
+//
+// The sign is always exact: when rounding could change it, the determinant
+// is evaluated again in exact arithmetic. The geometry functions of this
+// package build on it so that, for instance, a point lying exactly on an edge
+// is always found to be on it.
+func Orient2D(a, b, c Vec2) float64 {
+	acx, acy := a[0]-c[0], a[1]-c[1]
+	bcx, bcy := b[0]-c[0], b[1]-c[1]
+	left, right := acx*bcy, acy*bcx
+	if math.Abs(left) < orientTiny && acx != 0 && bcy != 0 ||
+		math.Abs(right) < orientTiny && acy != 0 && bcx != 0 {
+		return orient2DExact(a, b, c)
+	}
+	det := left - right
+
+	var sum float64
+	switch {
+	case left > 0:
+		if right <= 0 {
+			return det
+		}
+		sum = left + right
+	case left < 0:
+		if right >= 0 {
+			return det
+		}
+		sum = -left - right
+	default:
+		return det
+	}
+	if math.Abs(det) >= orientErrBound*sum {
+		return det
+	}
+	return orient2DExact(a, b, c)
+}
+
+func orient2DExact(a, b, c Vec2) float64 {
+	rat := func(x float64) *big.Rat {
+		return new(big.Rat).SetFloat64(x)
+	}
+	acx := rat(a[0]).Sub(rat(a[0]), rat(c[0]))
+	acy := rat(a[1]).Sub(rat(a[1]), rat(c[1]))
+	bcx := rat(b[0]).Sub(rat(b[0]), rat(c[0]))
+	bcy := rat(b[1]).Sub(rat(b[1]), rat(c[1]))
+	det := new(big.Rat).Sub(acx.Mul(acx, bcy), acy.Mul(acy, bcx))
+	f, _ := det.Float64()
+	if f == 0 && det.Sign() != 0 {
+		// too small for a float64, but the sign is what matters
+		return math.Copysign(math.SmallestNonzeroFloat64, float64(det.Sign()))
+	}
+	return f
+}
+
+// inBox reports whether p lies in the bounding box of a and b. For a point
+// collinear with a and b, this means it lies on the segment ab.
+func inBox(a, b, p Vec2) bool {
+	return min(a[0], b[0]) <= p[0] && p[0] <= max(a[0], b[0]) &&
+		min(a[1], b[1]) <= p[1] && p[1] <= max(a[1], b[1])
+}
+
+//
+// Segment
+//
+
+// Segment is the line segment between two points.
+type Segment struct {
+	A, B Vec2
+}
+
+// Length returns the length of s.
+func (s Segment) Length() float64 {
+	return s.A.DistanceTo(s.B)
+}
+
+// AABB returns the bounding box of s.
+func (s Segment) AABB() AABB {
+	return AABBOf(s.A, s.B)
+}
+
+// ClosestPoint returns the point of s closest to p.
+func (s Segment) ClosestPoint(p Vec2) Vec2 {
+	d := s.B.Sub(s.A)
+	l2 := d.Dot(d)
+	if l2 == 0 {
+		return s.A
+	}
+	t := p.Sub(s.A).Dot(d) / l2
+	switch {
+	case t <= 0:
+		return s.A
+	case t >= 1:
+		return s.B
+	}
+	return s.A.Add(d.Scale(t))
+}
+
+// DistanceTo returns the distance from p to the closest point of s.
+func (s Segment) DistanceTo(p Vec2) float64 {
+	return p.DistanceTo(s.ClosestPoint(p))
+}
+
+// Intersects reports whether s and o have at least one point in common,
+// including when they only touch or overlap along a line.
+func (s Segment) Intersects(o Segment) bool {
+	d1, d2 := Orient2D(o.A, o.B, s.A), Orient2D(o.A, o.B, s.B)
+	d3, d4 := Orient2D(s.A, s.B, o.A), Orient2D(s.A, s.B, o.B)
+	if (d1 > 0 && d2 < 0 || d1 < 0 && d2 > 0) && (d3 > 0 && d4 < 0 || d3 < 0 && d4 > 0) {
+		return true
+	}
+	return d1 == 0 && inBox(o.A, o.B, s.A) ||
+		d2 == 0 && inBox(o.A, o.B, s.B) ||
+		d3 == 0 && inBox(s.A, s.B, o.A) ||
+		d4 == 0 && inBox(s.A, s.B, o.B)
+}
+
+// Intersection returns a point common to s and o. When the segments overlap
+// along a line, it is an endpoint of the overlap.
+//
+// Returns:
+//   - The intersection point.
+//   - false if the segments do not intersect.
+func (s Segment) Intersection(o Segment) (Vec2, bool) {
+	if !s.Intersects(o) {
+		return Vec2{}, false
+	}
+	r, e := s.B.Sub(s.A), o.B.Sub(o.A)
+	if denom := r.Cross(e); denom != 0 {
+		t := o.A.Sub(s.A).Cross(e) / denom
+		return s.A.Add(r.Scale(math.Max(0, math.Min(1, t)))), true
+	}
+	// collinear segments, or segments reduced to a point
+	switch {
+	case Orient2D(o.A, o.B, s.A) == 0 && inBox(o.A, o.B, s.A):
+		return s.A, true
+	case Orient2D(o.A, o.B, s.B) == 0 && inBox(o.A, o.B, s.B):
+		return s.B, true
+	case Orient2D(s.A, s.B, o.A) == 0 && inBox(s.A, s.B, o.A):
+		return o.A, true
+	}
+	return o.B, true
+}
+
+//
+// Ray and Line
+//
+
+// Ray is the half-line that starts at Origin and extends along Dir.
+type Ray struct {
+	Origin, Dir Vec2
+}
+
+// At returns the point Origin + t·Dir.
+func (r Ray) At(t float64) Vec2 {
+	return r.Origin.Add(r.Dir.Scale(t))
+}
+
+// IntersectSegment returns where r first meets s, as the parameter t >= 0 of
+// the point At(t). When r runs along s, it is where r enters s.
+//
+// Returns:
+//   - The parameter of the first intersection.
+//   - false if r does not meet s.
+func (r Ray) IntersectSegment(s Segment) (float64, bool) {
+	e := s.B.Sub(s.A)
+	w := s.A.Sub(r.Origin)
+	denom := r.Dir.Cross(e)
+	if denom == 0 {
+		if w.Cross(r.Dir) != 0 || r.Dir == (Vec2{}) {
+			return 0, false
+		}
+		// collinear: the overlap of the ray with the segment, if any
+		l2 := r.Dir.Dot(r.Dir)
+		ta, tb := w.Dot(r.Dir)/l2, s.B.Sub(r.Origin).Dot(r.Dir)/l2
+		if max(ta, tb) < 0 {
+			return 0, false
+		}
+		return math.Max(0, min(ta, tb)), true
+	}
+	t, u := w.Cross(e)/denom, w.Cross(r.Dir)/denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// Line is the infinite line through P along Dir.
+type Line struct {
+	P, Dir Vec2
+}
+
+// LineThrough returns the line through a and b, directed from a to b.
+func LineThrough(a, b Vec2) Line {
+	return Line{P: a, Dir: b.Sub(a)}
+}
+
+// Side returns a value that is positive if p is to the left of l, looking
+// along Dir, negative if it is to the right and zero if it is on l. The sign
+// is exact for lines built by LineThrough, see Orient2D.
+func (l Line) Side(p Vec2) float64 {
+	return Orient2D(l.P, l.P.Add(l.Dir), p)
+}
+
+// Project returns the point of l closest to p.
+func (l Line) Project(p Vec2) Vec2 {
+	l2 := l.Dir.Dot(l.Dir)
+	if l2 == 0 {
+		return l.P
+	}
+	return l.P.Add(l.Dir.Scale(p.Sub(l.P).Dot(l.Dir) / l2))
+}
+
+// DistanceTo returns the distance from p to l.
+func (l Line) DistanceTo(p Vec2) float64 {
+	return p.DistanceTo(l.Project(p))
+}
+
+// Intersection returns the point where l and o cross.
+//
+// Returns:
+//   - The intersection point.
+//   - false if the lines are parallel, or the same line.
+func (l Line) Intersection(o Line) (Vec2, bool) {
+	denom := l.Dir.Cross(o.Dir)
+	if denom == 0 {
+		return Vec2{}, false
+	}
+	return l.P.Add(l.Dir.Scale(o.P.Sub(l.P).Cross(o.Dir) / denom)), true
+}
+
+//
+// AABB
+//
+
+// AABB is an axis-aligned bounding box, closed on all sides. A box whose Min
+// is greater than its Max along some axis is empty.
+type AABB struct {
+	Min, Max Vec2
+}
+
+// AABBOf returns the smallest box that contains all the points. With no
+// points it returns an empty box, which Union treats as neutral.
+func AABBOf(points ...Vec2) AABB {
+	b := AABB{Min: Vec2{math.Inf(1), math.Inf(1)}, Max: Vec2{math.Inf(-1), math.Inf(-1)}}
+	for _, p := range points {
+		b.Min = Vec2{math.Min(b.Min[0], p[0]), math.Min(b.Min[1], p[1])}
+		b.Max = Vec2{math.Max(b.Max[0], p[0]), math.Max(b.Max[1], p[1])}
+	}
+	return b
+}
+
+// Empty reports whether b contains no point.
+func (b AABB) Empty() bool {
+	return b.Min[0] > b.Max[0] || b.Min[1] > b.Max[1]
+}
+
+// Contains reports whether p lies in b or on its border.
+func (b AABB) Contains(p Vec2) bool {
+	return b.Min[0] <= p[0] && p[0] <= b.Max[0] && b.Min[1] <= p[1] && p[1] <= b.Max[1]
+}
+
+// Overlaps reports whether b and o have at least one point in common,
+// including when they only touch.
+func (b AABB) Overlaps(o AABB) bool {
+	return b.Min[0] <= o.Max[0] && o.Min[0] <= b.Max[0] &&
+		b.Min[1] <= o.Max[1] && o.Min[1] <= b.Max[1]
+}
+
+// Union returns the smallest box that contains both b and o.
+func (b AABB) Union(o AABB) AABB {
+	return AABB{
+		Min: Vec2{math.Min(b.Min[0], o.Min[0]), math.Min(b.Min[1], o.Min[1])},
+		Max: Vec2{math.Max(b.Max[0], o.Max[0]), math.Max(b.Max[1], o.Max[1])},
+	}
+}
+
+// ClipSegment returns the part of s inside b, with the Liang-Barsky
+// algorithm. Endpoints of s inside b are kept exactly.
+//
+// Returns:
+//   - The clipped segment, in the direction of s.
+//   - false if s does not cross b.
+func (b AABB) ClipSegment(s Segment) (Segment, bool) {
+	t0, t1, ok := b.clip(s.A, s.B.Sub(s.A), 0, 1)
+	if !ok {
+		return Segment{}, false
+	}
+	c := Segment{A: s.A, B: s.B}
+	if t0 > 0 {
+		c.A = s.A.Add(s.B.Sub(s.A).Scale(t0))
+	}
+	if t1 < 1 {
+		c.B = s.A.Add(s.B.Sub(s.A).Scale(t1))
+	}
+	return c, true
+}
+
+// ClipLine returns the part of l inside b.
+//
+// Returns:
+//   - The clipped segment, in the direction of l.
+//   - false if l does not cross b.
+func (b AABB) ClipLine(l Line) (Segment, bool) {
+	t0, t1, ok := b.clip(l.P, l.Dir, math.Inf(-1), math.Inf(1))
+	if !ok || math.IsInf(t0, 0) || math.IsInf(t1, 0) {
+		// a degenerate line is a point, and has no extent to clip
+		return Segment{}, false
+	}
+	return Segment{A: l.P.Add(l.Dir.Scale(t0)), B: l.P.Add(l.Dir.Scale(t1))}, true
+}
+
+// clip narrows the parameter range [t0, t1] of the points p + t·d to those
+// inside b.
+func (b AABB) clip(p, d Vec2, t0, t1 float64) (float64, float64, bool) {
+	if b.Empty() {
+		return 0, 0, false
+	}
+	for axis := range 2 {
+		// the two borders of the axis, as: den·t <= num
+		for _, bound := range [2][2]float64{
+			{-d[axis], p[axis] - b.Min[axis]},
+			{d[axis], b.Max[axis] - p[axis]},
+		} {
+			den, num := bound[0], bound[1]
+			switch {
+			case den == 0:
+				if num < 0 {
+					return 0, 0, false
+				}
+			case den < 0:
+				t0 = math.Max(t0, num/den)
+			default:
+				t1 = math.Min(t1, num/den)
+			}
+		}
+	}
+	return t0, t1, t0 <= t1
+}
+
+//
+// Circle
+//
+
+// Circle is the disk of the given radius around Center.
+type Circle struct {
+	Center Vec2
+	Radius float64
+}
+
+// Area returns the area of c.
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+// AABB returns the bounding box of c.
+func (c Circle) AABB() AABB {
+	r := Vec2{c.Radius, c.Radius}
+	return AABB{Min: c.Center.Sub(r), Max: c.Center.Add(r)}
+}
+
+// Contains reports whether p lies in c or on its border.
+func (c Circle) Contains(p Vec2) bool {
+	return c.Center.DistanceTo(p) <= c.Radius
+}
+
+// Overlaps reports whether c and o have at least one point in common.
+func (c Circle) Overlaps(o Circle) bool {
+	return c.Center.DistanceTo(o.Center) <= c.Radius+o.Radius
+}
+
+// IntersectsSegment reports whether s has at least one point in c.
+func (c Circle) IntersectsSegment(s Segment) bool {
+	return s.DistanceTo(c.Center) <= c.Radius
+}
+
+//
+// Polygon
+//
+
+// Polygon is a simple polygon given by its vertices in order, without
+// repeating the first vertex at the end. Counter-clockwise polygons have a
+// positive SignedArea.
+type Polygon []Vec2
+
+// SignedArea returns the area of p, positive if its vertices turn
+// counter-clockwise and negative otherwise.
+func (p Polygon) SignedArea() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+	// relative to the first vertex, which loses less precision far from the
+	// origin
+	acc := 0.0
+	for i := 1; i+1 < len(p); i++ {
+		acc += p[i].Sub(p[0]).Cross(p[i+1].Sub(p[0]))
+	}
+	return acc / 2
+}
+
+// Area returns the area of p.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// Perimeter returns the length of the border of p.
+func (p Polygon) Perimeter() float64 {
+	acc := 0.0
+	for i, v := range p {
+		acc += v.DistanceTo(p[(i+1)%len(p)])
+	}
+	return acc
+}
+
+// Centroid returns the center of mass of p, or the mean of its vertices if
+// its area is zero.
+func (p Polygon) Centroid() Vec2 {
+	if len(p) == 0 {
+		return Vec2{math.NaN(), math.NaN()}
+	}
+	var c Vec2
+	area := 0.0
+	for i := 1; i+1 < len(p); i++ {
+		a, b := p[i].Sub(p[0]), p[i+1].Sub(p[0])
+		w := a.Cross(b)
+		c = c.Add(a.Add(b).Scale(w))
+		area += w
+	}
+	if area == 0 {
+		var sum Vec2
+		for _, v := range p {
+			sum = sum.Add(v)
+		}
+		return sum.Scale(1 / float64(len(p)))
+	}
+	return p[0].Add(c.Scale(1 / (3 * area)))
+}
+
+// AABB returns the bounding box of p.
+func (p Polygon) AABB() AABB {
+	return AABBOf(p...)
+}
+
+// Contains reports whether q lies inside p or on its border. Self-crossing
+// polygons follow the nonzero winding rule.
+func (p Polygon) Contains(q Vec2) bool {
+	winding := 0
+	for i, a := range p {
+		b := p[(i+1)%len(p)]
+		o := Orient2D(a, b, q)
+		if o == 0 && inBox(a, b, q) {
+			return true
+		}
+		if a[1] <= q[1] {
+			if b[1] > q[1] && o > 0 {
+				winding++
+			}
+		} else if b[1] <= q[1] && o < 0 {
+			winding--
+		}
+	}
+	return winding != 0
+}
+
+// ConvexHull returns the convex hull of the points with Andrew's monotone
+// chain algorithm, counter-clockwise from the leftmost point, the lowest one
+// among equals. Points on the edges of the hull are left out. Fewer than 3
+// distinct points, or collinear points, give a degenerate hull of at most 2
+// vertices.
+func ConvexHull(points []Vec2) Polygon {
+	ps := slices.Clone(points)
+	slices.SortFunc(ps, func(a, b Vec2) int {
+		if c := cmp.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a[1], b[1])
+	})
+	ps = slices.Compact(ps)
+	if len(ps) < 3 {
+		return Polygon(ps)
+	}
+
+	hull := make(Polygon, 0, 2*len(ps))
+	// lower chain, then upper chain
+	for _, q := range ps {
+		for len(hull) >= 2 && Orient2D(hull[len(hull)-2], hull[len(hull)-1], q) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, q)
+	}
+	lower := len(hull) + 1
+	for i := len(ps) - 2; i >= 0; i-- {
+		for len(hull) >= lower && Orient2D(hull[len(hull)-2], hull[len(hull)-1], ps[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, ps[i])
+	}
+	// the last point is the first one again
+	return hull[:len(hull)-1]
+}
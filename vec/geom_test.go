@@ -0,0 +1,301 @@
+package vec
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+func TestOrient2D(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b, c Vec2
+		want    int
+	}{
+		{"counter-clockwise", Vec2{0, 0}, Vec2{1, 0}, Vec2{0, 1}, 1},
+		{"clockwise", Vec2{0, 0}, Vec2{0, 1}, Vec2{1, 0}, -1},
+		{"collinear", Vec2{0, 0}, Vec2{1, 1}, Vec2{3, 3}, 0},
+		{"tiny values", Vec2{0, 0}, Vec2{1e-300, 0}, Vec2{0, 1e-300}, 1},
+	}
+	for _, tt := range tests {
+		if got := sign(Orient2D(tt.a, tt.b, tt.c)); got != tt.want {
+			t.Errorf("%s: Orient2D sign was %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// points a few ULPs around a line, where the plain floating-point
+	// determinant gets the sign wrong (Kettner et al., "Classroom Examples of
+	// Robustness Problems in Geometric Computations")
+	b, c := Vec2{12, 12}, Vec2{24, 24}
+	wrong := 0
+	for i := range 64 {
+		for j := range 64 {
+			a := Vec2{0.5 + float64(i)*0x1p-53, 0.5 + float64(j)*0x1p-53}
+			exact := sign(orient2DExact(a, b, c))
+			if got := sign(Orient2D(a, b, c)); got != exact {
+				t.Fatalf("Orient2D(%v) sign was %d, want %d", a, got, exact)
+			}
+			naive := (a[0]-c[0])*(b[1]-c[1]) - (a[1]-c[1])*(b[0]-c[0])
+			if sign(naive) != exact {
+				wrong++
+			}
+		}
+	}
+	if wrong == 0 {
+		t.Errorf("the grid does not exercise the exact fallback")
+	}
+}
+
+func TestSegment(t *testing.T) {
+	s := Segment{Vec2{0, 0}, Vec2{4, 0}}
+	tests := []struct {
+		name  string
+		o     Segment
+		hit   bool
+		point Vec2
+	}{
+		{"crossing", Segment{Vec2{2, -1}, Vec2{2, 1}}, true, Vec2{2, 0}},
+		{"touching at an endpoint", Segment{Vec2{4, 0}, Vec2{5, 3}}, true, Vec2{4, 0}},
+		{"T junction", Segment{Vec2{1, 0}, Vec2{1, 5}}, true, Vec2{1, 0}},
+		{"collinear overlap", Segment{Vec2{3, 0}, Vec2{6, 0}}, true, Vec2{4, 0}},
+		{"collinear disjoint", Segment{Vec2{5, 0}, Vec2{6, 0}}, false, Vec2{}},
+		{"parallel", Segment{Vec2{0, 1}, Vec2{4, 1}}, false, Vec2{}},
+		{"missing", Segment{Vec2{5, -1}, Vec2{5, 1}}, false, Vec2{}},
+		{"point on segment", Segment{Vec2{3, 0}, Vec2{3, 0}}, true, Vec2{3, 0}},
+	}
+	for _, tt := range tests {
+		if got := s.Intersects(tt.o); got != tt.hit {
+			t.Errorf("%s: Intersects was %v", tt.name, got)
+		}
+		if got := tt.o.Intersects(s); got != tt.hit {
+			t.Errorf("%s: Intersects is not symmetric", tt.name)
+		}
+		p, ok := s.Intersection(tt.o)
+		if ok != tt.hit || p != tt.point {
+			t.Errorf("%s: Intersection was %v, %v, want %v", tt.name, p, ok, tt.point)
+		}
+	}
+
+	// an endpoint exactly on a diagonal that floating-point products miss
+	diag := Segment{Vec2{0.1, 0.1}, Vec2{0.7, 0.7}}
+	on := Vec2{0.3, 0.3}
+	if Orient2D(diag.A, diag.B, on) == 0 != (orient2DExact(diag.A, diag.B, on) == 0) {
+		t.Errorf("Orient2D disagrees with exact arithmetic")
+	}
+
+	closest := []struct{ p, want Vec2 }{
+		{Vec2{2, 3}, Vec2{2, 0}},
+		{Vec2{-2, 3}, Vec2{0, 0}},
+		{Vec2{9, -1}, Vec2{4, 0}},
+	}
+	for _, tt := range closest {
+		if got := s.ClosestPoint(tt.p); got != tt.want {
+			t.Errorf("ClosestPoint(%v) was %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := s.DistanceTo(Vec2{7, 4}); got != 5 {
+		t.Errorf("DistanceTo was %v, want 5", got)
+	}
+}
+
+func TestRayAndLine(t *testing.T) {
+	r := Ray{Origin: Vec2{0, 0}, Dir: Vec2{1, 0}}
+	tests := []struct {
+		name string
+		s    Segment
+		t    float64
+		hit  bool
+	}{
+		{"ahead", Segment{Vec2{3, -1}, Vec2{3, 1}}, 3, true},
+		{"behind", Segment{Vec2{-3, -1}, Vec2{-3, 1}}, 0, false},
+		{"past the end", Segment{Vec2{3, 1}, Vec2{3, 2}}, 0, false},
+		{"along, ahead", Segment{Vec2{5, 0}, Vec2{2, 0}}, 2, true},
+		{"along, around the origin", Segment{Vec2{-1, 0}, Vec2{2, 0}}, 0, true},
+		{"along, behind", Segment{Vec2{-5, 0}, Vec2{-2, 0}}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := r.IntersectSegment(tt.s)
+		if ok != tt.hit || got != tt.t {
+			t.Errorf("%s: IntersectSegment was %v, %v, want %v, %v", tt.name, got, ok, tt.t, tt.hit)
+		}
+	}
+	if got := r.At(2.5); got != (Vec2{2.5, 0}) {
+		t.Errorf("At was %v", got)
+	}
+
+	l := LineThrough(Vec2{0, 0}, Vec2{2, 2})
+	if l.Side(Vec2{0, 1}) <= 0 || l.Side(Vec2{1, 0}) >= 0 || l.Side(Vec2{5, 5}) != 0 {
+		t.Errorf("Side was incorrect")
+	}
+	if got := l.Project(Vec2{2, 0}); got != (Vec2{1, 1}) {
+		t.Errorf("Project was %v", got)
+	}
+	if got := l.DistanceTo(Vec2{2, 0}); math.Abs(got-math.Sqrt2) > 1e-15 {
+		t.Errorf("DistanceTo was %v", got)
+	}
+	if p, ok := l.Intersection(LineThrough(Vec2{0, 4}, Vec2{4, 0})); !ok || p != (Vec2{2, 2}) {
+		t.Errorf("Intersection was %v, %v", p, ok)
+	}
+	if _, ok := l.Intersection(LineThrough(Vec2{1, 0}, Vec2{2, 1})); ok {
+		t.Errorf("parallel lines must not intersect")
+	}
+}
+
+func TestAABB(t *testing.T) {
+	b := AABBOf(Vec2{1, 3}, Vec2{-1, 0}, Vec2{2, 1})
+	if b != (AABB{Vec2{-1, 0}, Vec2{2, 3}}) {
+		t.Fatalf("AABBOf was %v", b)
+	}
+	if !b.Contains(Vec2{2, 3}) || b.Contains(Vec2{2.1, 0}) {
+		t.Errorf("Contains was incorrect")
+	}
+	if !b.Overlaps(AABB{Vec2{2, 3}, Vec2{5, 5}}) || b.Overlaps(AABB{Vec2{2.5, 0}, Vec2{5, 5}}) {
+		t.Errorf("Overlaps was incorrect")
+	}
+	empty := AABBOf()
+	if !empty.Empty() || empty.Contains(Vec2{}) || empty.Overlaps(b) || empty.Union(b) != b {
+		t.Errorf("the empty box was handled incorrectly")
+	}
+
+	box := AABB{Vec2{0, 0}, Vec2{10, 10}}
+	clips := []struct {
+		name string
+		s    Segment
+		want Segment
+		ok   bool
+	}{
+		{"inside", Segment{Vec2{1, 1}, Vec2{2, 3}}, Segment{Vec2{1, 1}, Vec2{2, 3}}, true},
+		{"crossing", Segment{Vec2{-5, 5}, Vec2{15, 5}}, Segment{Vec2{0, 5}, Vec2{10, 5}}, true},
+		{"diagonal", Segment{Vec2{-2, -2}, Vec2{5, 5}}, Segment{Vec2{0, 0}, Vec2{5, 5}}, true},
+		{"outside", Segment{Vec2{-5, -1}, Vec2{15, -1}}, Segment{}, false},
+		{"corner miss", Segment{Vec2{9, 12}, Vec2{12, 9}}, Segment{}, false},
+		{"along an edge", Segment{Vec2{-1, 10}, Vec2{3, 10}}, Segment{Vec2{0, 10}, Vec2{3, 10}}, true},
+	}
+	for _, tt := range clips {
+		got, ok := box.ClipSegment(tt.s)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: ClipSegment was %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+	if got, ok := box.ClipLine(LineThrough(Vec2{5, 5}, Vec2{6, 5})); !ok || got != (Segment{Vec2{0, 5}, Vec2{10, 5}}) {
+		t.Errorf("ClipLine was %v, %v", got, ok)
+	}
+	if _, ok := box.ClipLine(LineThrough(Vec2{0, 11}, Vec2{1, 12})); ok {
+		t.Errorf("ClipLine of a missing line succeeded")
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Center: Vec2{1, 1}, Radius: 2}
+	if c.Area() != 4*math.Pi || c.AABB() != (AABB{Vec2{-1, -1}, Vec2{3, 3}}) {
+		t.Errorf("Area or AABB was incorrect")
+	}
+	if !c.Contains(Vec2{3, 1}) || c.Contains(Vec2{3, 3}) {
+		t.Errorf("Contains was incorrect")
+	}
+	if !c.Overlaps(Circle{Vec2{5, 1}, 2}) || c.Overlaps(Circle{Vec2{5, 5}, 2}) {
+		t.Errorf("Overlaps was incorrect")
+	}
+	if !c.IntersectsSegment(Segment{Vec2{-5, 2}, Vec2{5, 2}}) || c.IntersectsSegment(Segment{Vec2{4, -5}, Vec2{4, 5}}) {
+		t.Errorf("IntersectsSegment was incorrect")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	// an L shape, counter-clockwise
+	l := Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 3}, {0, 3}}
+	if got := l.SignedArea(); got != 6 {
+		t.Errorf("SignedArea was %v, want 6", got)
+	}
+	reversed := slices.Clone(l)
+	slices.Reverse(reversed)
+	if got := reversed.SignedArea(); got != -6 {
+		t.Errorf("SignedArea of the reversed polygon was %v, want -6", got)
+	}
+	// the bar [0,4]x[0,1] and the column [0,1]x[1,3]
+	want := Vec2{(4*2 + 2*0.5) / 6, (4*0.5 + 2*2) / 6}
+	if got := l.Centroid(); got.DistanceTo(want) > 1e-15 {
+		t.Errorf("Centroid was %v, want %v", got, want)
+	}
+	if got := reversed.Centroid(); got.DistanceTo(want) > 1e-15 {
+		t.Errorf("Centroid of the reversed polygon was %v, want %v", got, want)
+	}
+	if got := l.Perimeter(); got != 14 {
+		t.Errorf("Perimeter was %v, want 14", got)
+	}
+
+	// far from the origin, the area must not lose precision
+	far := Polygon{{1e9, 1e9}, {1e9 + 1, 1e9}, {1e9 + 1, 1e9 + 1}, {1e9, 1e9 + 1}}
+	if got := far.Area(); got != 1 {
+		t.Errorf("Area far from the origin was %v, want 1", got)
+	}
+	if got := (Polygon{{0, 0}, {2, 2}, {4, 4}}).Centroid(); got != (Vec2{2, 2}) {
+		t.Errorf("Centroid of a degenerate polygon was %v", got)
+	}
+
+	contains := []struct {
+		p    Vec2
+		want bool
+	}{
+		{Vec2{0.5, 2}, true},
+		{Vec2{3, 0.5}, true},
+		{Vec2{2, 2}, false},
+		{Vec2{-1, 0.5}, false},
+		{Vec2{4, 0.5}, true}, // on an edge
+		{Vec2{1, 1}, true},   // on the reflex vertex
+		{Vec2{2, 1}, true},   // on an edge, at the height of vertices
+		{Vec2{5, 1}, false},  // at the height of vertices
+	}
+	for _, tt := range contains {
+		if got := l.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%v) was %v, want %v", tt.p, got, tt.want)
+		}
+		if got := reversed.Contains(tt.p); got != tt.want {
+			t.Errorf("reversed Contains(%v) was %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []Vec2{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}, {1, 0}, {0, 1}, {2, 2}, {1, 2}}
+	want := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	if got := ConvexHull(points); !slices.Equal(got, want) {
+		t.Errorf("ConvexHull was %v, want %v", got, want)
+	}
+	if got := ConvexHull([]Vec2{{0, 0}, {1, 1}, {3, 3}, {2, 2}}); !slices.Equal(got, Polygon{{0, 0}, {3, 3}}) {
+		t.Errorf("ConvexHull of collinear points was %v", got)
+	}
+	if got := ConvexHull(nil); len(got) != 0 {
+		t.Errorf("ConvexHull of no points was %v", got)
+	}
+
+	// random points: the hull is convex, counter-clockwise and contains them
+	r := rand.New(rand.NewPCG(3, 1))
+	points = make([]Vec2, 1000)
+	for i := range points {
+		points[i] = Vec2{r.NormFloat64(), r.NormFloat64()}
+	}
+	hull := ConvexHull(points)
+	for i := range hull {
+		a, b, c := hull[i], hull[(i+1)%len(hull)], hull[(i+2)%len(hull)]
+		if Orient2D(a, b, c) <= 0 {
+			t.Fatalf("the hull turns clockwise or is flat at %v", b)
+		}
+	}
+	for _, p := range points {
+		if !hull.Contains(p) {
+			t.Fatalf("the hull does not contain %v", p)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package vec
+
+import (
+	"github.com/AntonyChR/go-utils/assert"
+	gmath "github.com/AntonyChR/go-utils/math"
+)
+
+// The methods and functions in this file complement the copying operations
+// of Vec the way array.MutMap complements array.Map: the *InPlace methods
+// overwrite the receiver, and the *To functions write into a destination
+// provided by the caller, which may be one of the operands. None of them
+// allocate. They panic if the sizes do not match.
+
+// AddInPlace adds u to v element-wise, modifying v.
+//
+// Returns:
+//   - v, with the sums.
+func (v *VecOf[T]) AddInPlace(u VecOf[T]) VecOf[T] {
+	return AddTo(*v, *v, u)
+}
+
+// SubInPlace subtracts u from v element-wise, modifying v.
+//
+// Returns:
+//   - v, with the differences.
+func (v *VecOf[T]) SubInPlace(u VecOf[T]) VecOf[T] {
+	return SubTo(*v, *v, u)
+}
+
+// ScaleInPlace multiplies every element of v by n, modifying v.
+//
+// Returns:
+//   - v, with the scaled elements.
+func (v *VecOf[T]) ScaleInPlace(n T) VecOf[T] {
+	return ScaleTo(*v, *v, n)
+}
+
+// UnitInPlace scales v to length 1, modifying v. A zero vector is left
+// unchanged.
+//
+// Returns:
+//   - v, normalized.
+func (v *VecOf[T]) UnitInPlace() VecOf[T] {
+	return UnitTo(*v, *v)
+}
+
+// MapInPlace applies cb to every element of v, modifying v.
+//
+// Returns:
+//   - v, with the transformed elements.
+func (v *VecOf[T]) MapInPlace(cb func(T) T) VecOf[T] {
+	return MapTo(*v, *v, cb)
+}
+
+// AddScaledInPlace adds a·x to v, modifying v. It is the BLAS axpy
+// operation y ← a·x + y with v as y.
+//
+// Returns:
+//   - v, with the result.
+func (v *VecOf[T]) AddScaledInPlace(a T, x VecOf[T]) VecOf[T] {
+	return AXPY(*v, a, x, *v)
+}
+
+// AddTo writes a + b into dst.
+//
+// Parameters:
+//   - dst: The destination, of the same size as a and b; it may be a or b.
+//   - a, b: The operands.
+//
+// Returns:
+//   - dst.
+func AddTo[T gmath.Number](dst, a, b VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(a), len(dst), "Vectors must have the same size")
+	assert.AssertEq(len(b), len(dst), "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = a[i] + b[i]
+	}
+	return dst
+}
+
+// SubTo writes a - b into dst. It follows the same conventions as AddTo.
+func SubTo[T gmath.Number](dst, a, b VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(a), len(dst), "Vectors must have the same size")
+	assert.AssertEq(len(b), len(dst), "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = a[i] - b[i]
+	}
+	return dst
+}
+
+// ScaleTo writes a·n into dst, which may be a.
+//
+// Returns:
+//   - dst.
+func ScaleTo[T gmath.Number](dst, a VecOf[T], n T) VecOf[T] {
+	assert.AssertEq(len(a), len(dst), "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = a[i] * n
+	}
+	return dst
+}
+
+// UnitTo writes a scaled to length 1 into dst, which may be a. If a is the
+// zero vector, dst is filled with zeros.
+//
+// Returns:
+//   - dst.
+func UnitTo[T gmath.Number](dst, a VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(a), len(dst), "Vectors must have the same size")
+	l := a.Norm()
+	if l == 0 {
+		clear(dst)
+		return dst
+	}
+	integer := isInteger[T]()
+	for i, e := range a {
+		dst[i] = fromFloat[T](float64(e)/l, integer)
+	}
+	return dst
+}
+
+// MapTo writes cb applied to every element of a into dst, which may be a.
+//
+// Returns:
+//   - dst.
+func MapTo[T gmath.Number](dst, a VecOf[T], cb func(T) T) VecOf[T] {
+	assert.AssertEq(len(a), len(dst), "Vectors must have the same size")
+	for i, e := range a {
+		dst[i] = cb(e)
+	}
+	return dst
+}
+
+// AXPY writes the fused operation a·x + y into dst, in a single pass and
+// without the temporary vector that x.Scale(a) followed by Add would create.
+//
+// Parameters:
+//   - dst: The destination, of the same size as x and y; it may be x or y.
+//   - a: The factor applied to x.
+//   - x, y: The operands.
+//
+// Returns:
+//   - dst.
+func AXPY[T gmath.Number](dst VecOf[T], a T, x, y VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(x), len(dst), "Vectors must have the same size")
+	assert.AssertEq(len(y), len(dst), "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = a*x[i] + y[i]
+	}
+	return dst
+}
+
+// AXPBY writes the fused operation a·x + b·y into dst. It follows the same
+// conventions as AXPY; with a = 1-t and b = t it linearly interpolates
+// between x and y.
+func AXPBY[T gmath.Number](dst VecOf[T], a T, x VecOf[T], b T, y VecOf[T]) VecOf[T] {
+	assert.AssertEq(len(x), len(dst), "Vectors must have the same size")
+	assert.AssertEq(len(y), len(dst), "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = a*x[i] + b*y[i]
+	}
+	return dst
+}
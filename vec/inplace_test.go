@@ -0,0 +1,131 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInPlace(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       func(v *Vec) Vec
+		expected Vec
+	}{
+		{"AddInPlace", func(v *Vec) Vec { return v.AddInPlace(Vec{1, 1, 1}) }, Vec{4, 5, 1}},
+		{"SubInPlace", func(v *Vec) Vec { return v.SubInPlace(Vec{1, 2, 3}) }, Vec{2, 2, -3}},
+		{"ScaleInPlace", func(v *Vec) Vec { return v.ScaleInPlace(-2) }, Vec{-6, -8, 0}},
+		{"UnitInPlace", func(v *Vec) Vec { return v.UnitInPlace() }, Vec{0.6, 0.8, 0}},
+		{"MapInPlace", func(v *Vec) Vec { return v.MapInPlace(math.Sqrt) }, Vec{math.Sqrt(3), 2, 0}},
+		{"AddScaledInPlace", func(v *Vec) Vec { return v.AddScaledInPlace(2, Vec{1, 0, 1}) }, Vec{5, 4, 2}},
+	}
+	for _, tt := range tests {
+		v := Vec{3, 4, 0}
+		got := tt.op(&v)
+		if !vecNear(got, tt.expected, 1e-15) || !vecNear(v, tt.expected, 1e-15) {
+			t.Errorf("%s was incorrect, got: %v, receiver: %v, want: %v", tt.name, got, v, tt.expected)
+		}
+		if &got[0] != &v[0] {
+			t.Errorf("%s must return the receiver", tt.name)
+		}
+	}
+
+	zero := Zero(3)
+	if got := zero.UnitInPlace(); !vecNear(got, Vec{0, 0, 0}, 0) {
+		t.Errorf("UnitInPlace of zero was incorrect, got: %v", got)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	a, b := Vec{1, 2, 3}, Vec{4, 5, 6}
+	dst := Zero(3)
+
+	for _, tt := range []struct {
+		name     string
+		op       func() Vec
+		expected Vec
+	}{
+		{"AddTo", func() Vec { return AddTo(dst, a, b) }, Vec{5, 7, 9}},
+		{"SubTo", func() Vec { return SubTo(dst, a, b) }, Vec{-3, -3, -3}},
+		{"ScaleTo", func() Vec { return ScaleTo(dst, a, 3) }, Vec{3, 6, 9}},
+		{"UnitTo", func() Vec { return UnitTo(dst, Vec{0, 0, 2}) }, Vec{0, 0, 1}},
+		{"MapTo", func() Vec { return MapTo(dst, a, func(x float64) float64 { return x * x }) }, Vec{1, 4, 9}},
+		{"AXPY", func() Vec { return AXPY(dst, 2, a, b) }, Vec{6, 9, 12}},
+		{"AXPBY", func() Vec { return AXPBY(dst, 0.5, a, 0.5, b) }, Vec{2.5, 3.5, 4.5}},
+	} {
+		if got := tt.op(); !vecNear(got, tt.expected, 1e-15) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, got, tt.expected)
+		}
+	}
+
+	// the destination may alias an operand
+	x := Vec{1, 2, 3}
+	if got := AXPY(x, 10, x, x); !vecNear(got, Vec{11, 22, 33}, 0) {
+		t.Errorf("AXPY with aliasing was incorrect, got: %v", got)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		AddTo(dst, a, b)
+		AXPY(dst, 0.5, a, dst)
+		dst.UnitInPlace()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v per run", allocs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a destination of the wrong size")
+		}
+	}()
+	AddTo(Zero(2), a, b)
+}
+
+func benchVectors(size int) (Vec, Vec) {
+	x, y := make(Vec, size), make(Vec, size)
+	for i := range size {
+		x[i], y[i] = float64(i), float64(size-i)
+	}
+	return x, y
+}
+
+func BenchmarkAdd(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		x = x.Add(y)
+	}
+}
+
+func BenchmarkAddTo(b *testing.B) {
+	x, y := benchVectors(1024)
+	dst := Zero(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		AddTo(dst, x, y)
+	}
+}
+
+func BenchmarkAddInPlace(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		x.AddInPlace(y)
+	}
+}
+
+func BenchmarkScaleAdd(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		s := x.Scale(0.5)
+		y = s.Add(y)
+	}
+}
+
+func BenchmarkAXPY(b *testing.B) {
+	x, y := benchVectors(1024)
+	b.ReportAllocs()
+	for b.Loop() {
+		AXPY(y, 0.5, x, y)
+	}
+}
@@ -0,0 +1,266 @@
+package vec
+
+import (
+	"fmt"
+	"slices"
+)
+
+// State is the position and velocity of a body, or of a whole system with
+// the coordinates of its bodies concatenated.
+type State struct {
+	Pos, Vel Vec
+}
+
+// Force writes into acc the acceleration, that is the force divided by the
+// mass, of a system at position pos with velocity vel at time t. It must not
+// modify pos or vel, and must not allocate for the steps to be
+// allocation-free.
+type Force func(t float64, pos, vel, acc Vec)
+
+// Integrator advances a State in time.
+//
+// The integrators of this package keep scratch vectors sized on the first
+// step, and then step without allocating. An integrator is not safe for
+// concurrent use: give each goroutine its own.
+type Integrator interface {
+	// Step advances s in place from time t to t+dt. Pos and Vel must have
+	// the same size.
+	Step(s *State, t, dt float64)
+}
+
+// Euler is the explicit Euler method: first order, and its energy grows
+// without bound for oscillating systems. It is mostly useful as a reference.
+type Euler struct {
+	force Force
+	acc   Vec
+}
+
+// NewEuler creates an explicit Euler integrator for the given force.
+func NewEuler(f Force) *Euler {
+	return &Euler{force: f}
+}
+
+// Step advances s by dt, moving along the velocity and then updating it.
+func (e *Euler) Step(s *State, t, dt float64) {
+	e.acc = resize(e.acc, len(s.Pos))
+	e.force(t, s.Pos, s.Vel, e.acc)
+	s.Pos.AddScaledInPlace(dt, s.Vel)
+	s.Vel.AddScaledInPlace(dt, e.acc)
+}
+
+// SemiImplicitEuler is the semi-implicit (symplectic) Euler method: first
+// order like Euler, for the same single force evaluation per step, but its
+// energy stays bounded for oscillating systems. It is the usual choice for
+// games.
+type SemiImplicitEuler struct {
+	force Force
+	acc   Vec
+}
+
+// NewSemiImplicitEuler creates a semi-implicit Euler integrator for the given
+// force.
+func NewSemiImplicitEuler(f Force) *SemiImplicitEuler {
+	return &SemiImplicitEuler{force: f}
+}
+
+// Step advances s by dt, updating the velocity and then moving along the new
+// one.
+func (e *SemiImplicitEuler) Step(s *State, t, dt float64) {
+	e.acc = resize(e.acc, len(s.Pos))
+	e.force(t, s.Pos, s.Vel, e.acc)
+	s.Vel.AddScaledInPlace(dt, e.acc)
+	s.Pos.AddScaledInPlace(dt, s.Vel)
+}
+
+// Verlet is the position (Störmer) Verlet method, which moves from the
+// current and previous positions: second order and symplectic for the
+// position, with a single force evaluation per step.
+//
+// The velocity is not part of the method; Step estimates it from the last
+// two positions, to first order, and passes that estimate to the force.
+// When the step size changes, or the position is modified between steps,
+// the previous position is rebuilt from the velocity.
+type Verlet struct {
+	force     Force
+	acc, prev Vec
+	cur       Vec // the position at the end of the last step
+	dt        float64
+}
+
+// NewVerlet creates a position Verlet integrator for the given force.
+func NewVerlet(f Force) *Verlet {
+	return &Verlet{force: f}
+}
+
+// Step advances s by dt.
+func (v *Verlet) Step(s *State, t, dt float64) {
+	n := len(s.Pos)
+	v.acc = resize(v.acc, n)
+	v.force(t, s.Pos, s.Vel, v.acc)
+	if dt != v.dt || !slices.Equal(s.Pos, v.cur) {
+		// x(t-dt) from the Taylor expansion around t
+		v.prev = resize(v.prev, n)
+		AXPY(v.prev, -dt, s.Vel, s.Pos)
+		v.prev.AddScaledInPlace(dt*dt/2, v.acc)
+		v.dt = dt
+	}
+	// x(t+dt) = 2·x(t) - x(t-dt) + a·dt², keeping x(t) in prev
+	for i, x := range s.Pos {
+		s.Pos[i] = 2*x - v.prev[i] + v.acc[i]*dt*dt
+		v.prev[i] = x
+	}
+	SubTo(s.Vel, s.Pos, v.prev)
+	s.Vel.ScaleInPlace(1 / dt)
+	v.cur = resize(v.cur, n)
+	copy(v.cur, s.Pos)
+}
+
+// VelocityVerlet is the velocity Verlet method: second order and symplectic,
+// with the position and velocity known at the same instant. It evaluates the
+// force twice per step; for a force that does not depend on the velocity,
+// the second evaluation is exact.
+type VelocityVerlet struct {
+	force     Force
+	acc, next Vec
+	halfVel   Vec
+}
+
+// NewVelocityVerlet creates a velocity Verlet integrator for the given force.
+func NewVelocityVerlet(f Force) *VelocityVerlet {
+	return &VelocityVerlet{force: f}
+}
+
+// Step advances s by dt.
+func (v *VelocityVerlet) Step(s *State, t, dt float64) {
+	n := len(s.Pos)
+	v.acc, v.next, v.halfVel = resize(v.acc, n), resize(v.next, n), resize(v.halfVel, n)
+	v.force(t, s.Pos, s.Vel, v.acc)
+	// v(t+dt/2), then x(t+dt) = x(t) + v(t+dt/2)·dt
+	AXPY(v.halfVel, dt/2, v.acc, s.Vel)
+	s.Pos.AddScaledInPlace(dt, v.halfVel)
+	// the velocity at t+dt is not known yet: the force sees v(t+dt/2)
+	v.force(t+dt, s.Pos, v.halfVel, v.next)
+	AXPY(s.Vel, dt/2, v.next, v.halfVel)
+}
+
+// RK4 is the classic fourth-order Runge-Kutta method: very accurate for
+// smooth forces at four force evaluations per step, but not symplectic, so
+// the energy of an oscillating system slowly decays over long runs.
+type RK4 struct {
+	force          Force
+	a1, a2, a3, a4 Vec
+	x, v, sumX     Vec
+}
+
+// NewRK4 creates a fourth-order Runge-Kutta integrator for the given force.
+func NewRK4(f Force) *RK4 {
+	return &RK4{force: f}
+}
+
+// Step advances s by dt.
+func (r *RK4) Step(s *State, t, dt float64) {
+	n := len(s.Pos)
+	for _, b := range []*Vec{&r.a1, &r.a2, &r.a3, &r.a4, &r.x, &r.v, &r.sumX} {
+		*b = resize(*b, n)
+	}
+	pos, vel := s.Pos, s.Vel
+
+	// the derivative of the position at each stage is the velocity of the
+	// stage: vel, then r.v after each update
+	r.force(t, pos, vel, r.a1)
+
+	AXPY(r.x, dt/2, vel, pos)
+	AXPY(r.v, dt/2, r.a1, vel)
+	r.force(t+dt/2, r.x, r.v, r.a2)
+	AXPY(r.sumX, 2, r.v, vel)
+
+	AXPY(r.x, dt/2, r.v, pos)
+	AXPY(r.v, dt/2, r.a2, vel)
+	r.force(t+dt/2, r.x, r.v, r.a3)
+	r.sumX.AddScaledInPlace(2, r.v)
+
+	AXPY(r.x, dt, r.v, pos)
+	AXPY(r.v, dt, r.a3, vel)
+	r.force(t+dt, r.x, r.v, r.a4)
+	r.sumX.AddInPlace(r.v)
+
+	pos.AddScaledInPlace(dt/6, r.sumX)
+	for i := range vel {
+		vel[i] += dt / 6 * (r.a1[i] + 2*r.a2[i] + 2*r.a3[i] + r.a4[i])
+	}
+}
+
+// Simulation runs an Integrator with a fixed timestep from variable frame
+// times, as in "Fix Your Timestep!" by Glenn Fiedler. Elapsed time
+// accumulates until it covers whole steps, and the remainder is used to
+// interpolate between the last two states for smooth rendering. A fixed
+// timestep keeps the simulation deterministic and stable whatever the frame
+// rate.
+type Simulation struct {
+	Integrator Integrator
+	Dt         float64 // The fixed timestep
+	// MaxSteps bounds the steps of a single Advance, so that a simulation
+	// slower than real time drops time instead of falling further and
+	// further behind. Zero means no limit.
+	MaxSteps int
+	State    State   // The state after the last step
+	Time     float64 // The time of State
+
+	prev        State
+	accumulator float64
+}
+
+// NewSimulation creates a simulation starting from s at time 0. The
+// simulation steps s in place.
+func NewSimulation(integrator Integrator, s State, dt float64) *Simulation {
+	return &Simulation{
+		Integrator: integrator,
+		Dt:         dt,
+		State:      s,
+		prev:       State{Pos: slices.Clone(s.Pos), Vel: slices.Clone(s.Vel)},
+	}
+}
+
+// Advance adds elapsed time to the simulation and runs every whole step it
+// covers. It does not allocate once the integrator has run a step. It
+// panics if Dt is not positive.
+//
+// Returns:
+//   - The number of steps run.
+func (sim *Simulation) Advance(elapsed float64) int {
+	if !(sim.Dt > 0) {
+		panic(fmt.Sprintf("vec: simulation timestep %v", sim.Dt))
+	}
+	sim.accumulator += elapsed
+	steps := 0
+	for sim.accumulator >= sim.Dt {
+		if sim.MaxSteps > 0 && steps == sim.MaxSteps {
+			sim.accumulator = 0
+			break
+		}
+		copy(sim.prev.Pos, sim.State.Pos)
+		copy(sim.prev.Vel, sim.State.Vel)
+		sim.Integrator.Step(&sim.State, sim.Time, sim.Dt)
+		sim.Time += sim.Dt
+		sim.accumulator -= sim.Dt
+		steps++
+	}
+	return steps
+}
+
+// Alpha returns how far the accumulated time is between the last step and
+// the next one, in [0, 1).
+func (sim *Simulation) Alpha() float64 {
+	return sim.accumulator / sim.Dt
+}
+
+// Interpolate writes into dst the state blended between the last two steps
+// by Alpha, which lags one step behind the simulation but moves smoothly
+// between frames. The vectors of dst are reused if they are large enough.
+func (sim *Simulation) Interpolate(dst *State) {
+	n := len(sim.State.Pos)
+	dst.Pos, dst.Vel = resize(dst.Pos, n), resize(dst.Vel, n)
+	a := sim.Alpha()
+	AXPBY(dst.Pos, 1-a, sim.prev.Pos, a, sim.State.Pos)
+	AXPBY(dst.Vel, 1-a, sim.prev.Vel, a, sim.State.Vel)
+}
@@ -0,0 +1,233 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+// spring is a harmonic oscillator of angular frequency 1: x(t) = cos t from
+// x = 1 at rest, with the constant energy ½v² + ½x².
+func spring(t float64, pos, vel, acc Vec) {
+	ScaleTo(acc, pos, -1)
+}
+
+func springEnergy(s State) float64 {
+	return (s.Vel.Dot(s.Vel) + s.Pos.Dot(s.Pos)) / 2
+}
+
+func TestIntegratorEnergyDrift(t *testing.T) {
+	const (
+		dt    = 0.01
+		steps = 10000 // about 16 periods
+	)
+	tests := []struct {
+		name     string
+		integ    Integrator
+		minDrift float64
+		maxDrift float64
+	}{
+		// explicit Euler gains energy at every step
+		{"euler", NewEuler(spring), 0.5, math.Inf(1)},
+		// the symplectic methods oscillate around the true energy
+		{"semi-implicit euler", NewSemiImplicitEuler(spring), 0, 0.01},
+		{"verlet", NewVerlet(spring), 0, 0.01},
+		{"velocity verlet", NewVelocityVerlet(spring), 0, 1e-4},
+		{"rk4", NewRK4(spring), 0, 1e-8},
+	}
+	for _, tt := range tests {
+		s := State{Pos: Vec{1}, Vel: Vec{0}}
+		e0 := springEnergy(s)
+		drift := 0.0
+		for i := range steps {
+			tt.integ.Step(&s, float64(i)*dt, dt)
+			drift = max(drift, math.Abs(springEnergy(s)-e0)/e0)
+		}
+		if drift < tt.minDrift || drift > tt.maxDrift {
+			t.Errorf("%s: the energy drifted by %v, want within [%v, %v]", tt.name, drift, tt.minDrift, tt.maxDrift)
+		}
+	}
+}
+
+func TestIntegratorAccuracy(t *testing.T) {
+	// a 2D orbit: x(t) = (cos t, sin t)
+	const dt = 0.01
+	tests := []struct {
+		name  string
+		integ Integrator
+		eps   float64
+	}{
+		{"semi-implicit euler", NewSemiImplicitEuler(spring), 0.05},
+		{"verlet", NewVerlet(spring), 1e-3},
+		{"velocity verlet", NewVelocityVerlet(spring), 1e-3},
+		{"rk4", NewRK4(spring), 1e-9},
+	}
+	for _, tt := range tests {
+		s := State{Pos: Vec{1, 0}, Vel: Vec{0, 1}}
+		tm := 0.0
+		for range 1000 {
+			tt.integ.Step(&s, tm, dt)
+			tm += dt
+		}
+		if expected := (Vec{math.Cos(tm), math.Sin(tm)}); !vecNear(s.Pos, expected, tt.eps) {
+			t.Errorf("%s: the position was %v, want %v", tt.name, s.Pos, expected)
+		}
+	}
+}
+
+func TestIntegratorForceArguments(t *testing.T) {
+	// a velocity dependent force: linear drag, v(t) = e^-t
+	drag := func(t float64, pos, vel, acc Vec) {
+		ScaleTo(acc, vel, -1)
+	}
+	s := State{Pos: Vec{0}, Vel: Vec{1}}
+	integ := NewRK4(drag)
+	for i := range 100 {
+		integ.Step(&s, float64(i)*0.01, 0.01)
+	}
+	if got, want := s.Vel[0], math.Exp(-1); math.Abs(got-want) > 1e-10 {
+		t.Errorf("the velocity was %v, want %v", got, want)
+	}
+	if got, want := s.Pos[0], 1-math.Exp(-1); math.Abs(got-want) > 1e-10 {
+		t.Errorf("the position was %v, want %v", got, want)
+	}
+
+	// a time dependent force: a = t gives x = t³/6
+	ramp := func(t float64, pos, vel, acc Vec) {
+		acc[0] = t
+	}
+	s = State{Pos: Vec{0}, Vel: Vec{0}}
+	integ = NewRK4(ramp)
+	for i := range 100 {
+		integ.Step(&s, float64(i)*0.01, 0.01)
+	}
+	if got := s.Pos[0]; math.Abs(got-1.0/6) > 1e-12 {
+		t.Errorf("the position was %v, want %v", got, 1.0/6)
+	}
+}
+
+func TestVerletRestart(t *testing.T) {
+	// moving the body between steps must not make up a velocity from the jump
+	s := State{Pos: Vec{0}, Vel: Vec{1}}
+	free := func(t float64, pos, vel, acc Vec) { acc[0] = 0 }
+	integ := NewVerlet(free)
+	integ.Step(&s, 0, 0.1)
+	s.Pos[0] = 10
+	integ.Step(&s, 0.1, 0.1)
+	if !vecNear(s.Pos, Vec{10.1}, 1e-12) || !vecNear(s.Vel, Vec{1}, 1e-12) {
+		t.Errorf("Verlet did not restart from the new position, got: %+v", s)
+	}
+	// and neither must changing the step
+	integ.Step(&s, 0.2, 0.5)
+	if !vecNear(s.Pos, Vec{10.6}, 1e-12) || !vecNear(s.Vel, Vec{1}, 1e-12) {
+		t.Errorf("Verlet did not restart with the new step, got: %+v", s)
+	}
+}
+
+func TestIntegratorAllocs(t *testing.T) {
+	integrators := map[string]Integrator{
+		"euler":               NewEuler(spring),
+		"semi-implicit euler": NewSemiImplicitEuler(spring),
+		"verlet":              NewVerlet(spring),
+		"velocity verlet":     NewVelocityVerlet(spring),
+		"rk4":                 NewRK4(spring),
+	}
+	for name, integ := range integrators {
+		s := State{Pos: Vec{1, 2, 3}, Vel: Vec{0, 0, 0}}
+		integ.Step(&s, 0, 0.01)
+		if allocs := testing.AllocsPerRun(100, func() { integ.Step(&s, 0, 0.01) }); allocs != 0 {
+			t.Errorf("%s: Step allocated %v times", name, allocs)
+		}
+	}
+
+	sim := NewSimulation(NewRK4(spring), State{Pos: Vec{1, 2, 3}, Vel: Vec{0, 0, 0}}, 0.01)
+	sim.Advance(0.01)
+	var dst State
+	sim.Interpolate(&dst)
+	allocs := testing.AllocsPerRun(100, func() {
+		sim.Advance(0.025)
+		sim.Interpolate(&dst)
+	})
+	if allocs != 0 {
+		t.Errorf("the simulation allocated %v times", allocs)
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	// constant velocity, so the interpolated position is exact
+	free := func(t float64, pos, vel, acc Vec) { acc[0] = 0 }
+	sim := NewSimulation(NewSemiImplicitEuler(free), State{Pos: Vec{0}, Vel: Vec{1}}, 0.25)
+
+	var dst State
+	sim.Interpolate(&dst)
+	if !vecNear(dst.Pos, Vec{0}, 0) || !vecNear(dst.Vel, Vec{1}, 0) {
+		t.Errorf("Interpolate before a step was incorrect, got: %+v", dst)
+	}
+
+	tests := []struct {
+		elapsed float64
+		steps   int
+		time    float64
+		alpha   float64
+	}{
+		{0.1, 0, 0, 0.4},
+		{0.1, 0, 0, 0.8},
+		{0.1, 1, 0.25, 0.2},
+		{0.6, 2, 0.75, 0.6},
+		{0.1, 1, 1, 0},
+	}
+	for i, tt := range tests {
+		if steps := sim.Advance(tt.elapsed); steps != tt.steps {
+			t.Errorf("frame %d: %d steps, want %d", i, steps, tt.steps)
+		}
+		if math.Abs(sim.Time-tt.time) > 1e-12 || math.Abs(sim.Alpha()-tt.alpha) > 1e-12 {
+			t.Errorf("frame %d: time %v and alpha %v, want %v and %v", i, sim.Time, sim.Alpha(), tt.time, tt.alpha)
+		}
+		if !vecNear(sim.State.Pos, Vec{sim.Time}, 1e-12) {
+			t.Errorf("frame %d: the position was %v, want %v", i, sim.State.Pos, sim.Time)
+		}
+		// the rendered state lags one step behind
+		sim.Interpolate(&dst)
+		if want := max(sim.Time-sim.Dt, 0) + sim.Alpha()*sim.Dt; sim.Time > 0 && !vecNear(dst.Pos, Vec{want}, 1e-12) {
+			t.Errorf("frame %d: the interpolated position was %v, want %v", i, dst.Pos, want)
+		}
+	}
+
+	// a long stall runs at most MaxSteps and drops the rest
+	sim.MaxSteps = 3
+	if steps := sim.Advance(10); steps != 3 || sim.Alpha() != 0 {
+		t.Errorf("MaxSteps: %d steps and alpha %v, want 3 and 0", steps, sim.Alpha())
+	}
+	if math.Abs(sim.Time-1.75) > 1e-12 {
+		t.Errorf("MaxSteps: time %v, want 1.75", sim.Time)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a zero timestep")
+		}
+	}()
+	sim.Dt = 0
+	sim.Advance(1)
+}
+
+func BenchmarkIntegratorStep(b *testing.B) {
+	integrators := []struct {
+		name  string
+		integ Integrator
+	}{
+		{"SemiImplicitEuler", NewSemiImplicitEuler(spring)},
+		{"Verlet", NewVerlet(spring)},
+		{"VelocityVerlet", NewVelocityVerlet(spring)},
+		{"RK4", NewRK4(spring)},
+	}
+	for _, bb := range integrators {
+		b.Run(bb.name, func(b *testing.B) {
+			s := State{Pos: Rand(300), Vel: Rand(300)}
+			tm := 0.0
+			for b.Loop() {
+				bb.integ.Step(&s, tm, 1e-3)
+				tm += 1e-3
+			}
+		})
+	}
+}
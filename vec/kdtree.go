@@ -0,0 +1,293 @@
+package vec
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// KDTree is a k-d tree over points of any dimension, for nearest-neighbor and
+// radius queries that do not scan every point.
+//
+// Each point keeps the index it was given when the tree was built or when it
+// was inserted, and queries report that index. Removed points are only marked
+// as such, and the tree is rebuilt once they outnumber the live ones.
+// Insertions extend the tree without rebalancing it: call Rebuild after many
+// of them.
+//
+// Queries can prune the search with metrics that implement AxisBounder; any
+// other Metric works but compares the query against every point.
+type KDTree struct {
+	dim    int
+	metric Metric
+	bound  AxisBounder // nil if the metric cannot prune
+	points []Vec       // by index; nil once removed
+	root   *kdNode
+	live   int
+	dead   int
+}
+
+type kdNode struct {
+	id          int
+	axis        int
+	split       float64 // points[id][axis], kept after the point is removed
+	left, right *kdNode // coordinates <= split on the left, >= on the right
+}
+
+// Neighbor is a point found by a KDTree query.
+type Neighbor struct {
+	Index int     // Index of the point in the tree
+	Point Vec     // The point itself, shared with the tree: do not modify it
+	Dist  float64 // Distance from the query to the point
+}
+
+// NewKDTree creates an empty tree for points of the given dimension.
+//
+// Parameters:
+//   - dim: The dimension of the points, at least 1.
+//   - metric: The distance used by the queries; nil means Euclidean.
+func NewKDTree(dim int, metric Metric) *KDTree {
+	if dim < 1 {
+		panic(fmt.Sprintf("vec: k-d tree of dimension %d", dim))
+	}
+	if metric == nil {
+		metric = Euclidean{}
+	}
+	bound, _ := metric.(AxisBounder)
+	return &KDTree{dim: dim, metric: metric, bound: bound}
+}
+
+// KDTreeFrom builds a balanced tree from a slice of points. Point i of the
+// slice gets index i. The points are copied.
+//
+// Parameters:
+//   - points: The points, all of the same dimension.
+//   - metric: The distance used by the queries; nil means Euclidean.
+//
+// Returns:
+//   - ErrShape if the slice is empty, the points do not all have the same
+//     dimension or that dimension is 0.
+func KDTreeFrom(points []Vec, metric Metric) (*KDTree, error) {
+	if len(points) == 0 || len(points[0]) == 0 {
+		return nil, fmt.Errorf("%w: a k-d tree needs points of dimension at least 1", ErrShape)
+	}
+	t := NewKDTree(len(points[0]), metric)
+	t.points = make([]Vec, len(points))
+	for i, p := range points {
+		if err := t.checkDim(p); err != nil {
+			return nil, fmt.Errorf("point %d: %w", i, err)
+		}
+		t.points[i] = slices.Clone(p)
+	}
+	t.live = len(points)
+	t.Rebuild()
+	return t, nil
+}
+
+// Len returns the number of points in the tree, not counting removed ones.
+func (t *KDTree) Len() int {
+	return t.live
+}
+
+// Dim returns the dimension of the points.
+func (t *KDTree) Dim() int {
+	return t.dim
+}
+
+func (t *KDTree) checkDim(v Vec) error {
+	if len(v) != t.dim {
+		return fmt.Errorf("%w: vector of dimension %d for a tree of dimension %d", ErrShape, len(v), t.dim)
+	}
+	return nil
+}
+
+// Insert adds a copy of p to the tree.
+//
+// Returns:
+//   - The index of the new point.
+//   - ErrShape if p does not have the dimension of the tree.
+func (t *KDTree) Insert(p Vec) (int, error) {
+	if err := t.checkDim(p); err != nil {
+		return 0, err
+	}
+	id := len(t.points)
+	t.points = append(t.points, slices.Clone(p))
+	t.live++
+
+	link, axis := &t.root, 0
+	for *link != nil {
+		n := *link
+		if p[n.axis] < n.split {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		axis = (n.axis + 1) % t.dim
+	}
+	*link = &kdNode{id: id, axis: axis, split: p[axis]}
+	return id, nil
+}
+
+// Remove removes the point with the given index.
+//
+// Returns:
+//   - false if there is no such point, or it was already removed.
+func (t *KDTree) Remove(index int) bool {
+	if index < 0 || index >= len(t.points) || t.points[index] == nil {
+		return false
+	}
+	t.points[index] = nil
+	t.live--
+	t.dead++
+	if t.dead > t.live {
+		t.Rebuild()
+	}
+	return true
+}
+
+// Rebuild rebuilds a balanced tree from the remaining points, dropping the
+// removed ones. Indexes are preserved.
+func (t *KDTree) Rebuild() {
+	ids := make([]int, 0, t.live)
+	for id, p := range t.points {
+		if p != nil {
+			ids = append(ids, id)
+		}
+	}
+	t.root = t.build(ids)
+	t.dead = 0
+}
+
+// build splits the points at the median of the coordinate along which they
+// are most spread out.
+func (t *KDTree) build(ids []int) *kdNode {
+	if len(ids) == 0 {
+		return nil
+	}
+	axis, spread := 0, -1.0
+	for d := range t.dim {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, id := range ids {
+			lo, hi = min(lo, t.points[id][d]), max(hi, t.points[id][d])
+		}
+		if hi-lo > spread {
+			axis, spread = d, hi-lo
+		}
+	}
+	slices.SortFunc(ids, func(a, b int) int {
+		return cmp.Compare(t.points[a][axis], t.points[b][axis])
+	})
+	m := len(ids) / 2
+	return &kdNode{
+		id:    ids[m],
+		axis:  axis,
+		split: t.points[ids[m]][axis],
+		left:  t.build(ids[:m]),
+		right: t.build(ids[m+1:]),
+	}
+}
+
+// reachable reports whether the far side of a split at distance diff from
+// the query may hold points within distance r.
+func (t *KDTree) reachable(diff, r float64) bool {
+	return t.bound == nil || t.bound.AxisBound(math.Abs(diff)) <= r
+}
+
+// compareNeighbors orders neighbors by distance, then by index.
+func compareNeighbors(a, b Neighbor) int {
+	if c := cmp.Compare(a.Dist, b.Dist); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Index, b.Index)
+}
+
+// KNearest returns the k points closest to q.
+//
+// Parameters:
+//   - q: The query point, of the dimension of the tree.
+//   - k: The number of neighbors; fewer are returned if the tree is smaller.
+//
+// Returns:
+//   - The neighbors, closest first; ties go to the lowest index.
+//   - ErrShape if q does not have the dimension of the tree.
+func (t *KDTree) KNearest(q Vec, k int) ([]Neighbor, error) {
+	if err := t.checkDim(q); err != nil {
+		return nil, err
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+	best := make([]Neighbor, 0, min(k, t.live))
+	t.kNearest(t.root, q, k, &best)
+	return best, nil
+}
+
+func (t *KDTree) kNearest(n *kdNode, q Vec, k int, best *[]Neighbor) {
+	if n == nil {
+		return
+	}
+	if p := t.points[n.id]; p != nil {
+		nb := Neighbor{Index: n.id, Point: p, Dist: t.metric.Distance(q, p)}
+		// best is sorted and holds at most k neighbors
+		i, _ := slices.BinarySearchFunc(*best, nb, compareNeighbors)
+		if len(*best) < k {
+			*best = slices.Insert(*best, i, nb)
+		} else if i < k {
+			copy((*best)[i+1:], (*best)[i:k-1])
+			(*best)[i] = nb
+		}
+	}
+	diff := q[n.axis] - n.split
+	near, far := n.left, n.right
+	if diff >= 0 {
+		near, far = far, near
+	}
+	t.kNearest(near, q, k, best)
+	if len(*best) < k || t.reachable(diff, (*best)[len(*best)-1].Dist) {
+		t.kNearest(far, q, k, best)
+	}
+}
+
+// Nearest returns the point closest to q, or false if the tree is empty. It
+// follows the conventions of KNearest.
+func (t *KDTree) Nearest(q Vec) (Neighbor, bool, error) {
+	best, err := t.KNearest(q, 1)
+	if err != nil || len(best) == 0 {
+		return Neighbor{}, false, err
+	}
+	return best[0], true, nil
+}
+
+// Radius returns the points within distance r of q, inclusive.
+//
+// Returns:
+//   - The neighbors, closest first; ties go to the lowest index.
+//   - ErrShape if q does not have the dimension of the tree.
+func (t *KDTree) Radius(q Vec, r float64) ([]Neighbor, error) {
+	if err := t.checkDim(q); err != nil {
+		return nil, err
+	}
+	var found []Neighbor
+	t.radius(t.root, q, r, &found)
+	slices.SortFunc(found, compareNeighbors)
+	return found, nil
+}
+
+func (t *KDTree) radius(n *kdNode, q Vec, r float64, found *[]Neighbor) {
+	if n == nil {
+		return
+	}
+	if p := t.points[n.id]; p != nil {
+		if d := t.metric.Distance(q, p); d <= r {
+			*found = append(*found, Neighbor{Index: n.id, Point: p, Dist: d})
+		}
+	}
+	diff := q[n.axis] - n.split
+	if diff <= 0 || t.reachable(diff, r) {
+		t.radius(n.left, q, r, found)
+	}
+	if diff >= 0 || t.reachable(diff, r) {
+		t.radius(n.right, q, r, found)
+	}
+}
@@ -0,0 +1,195 @@
+package vec
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// bruteForce returns every live point sorted like the tree queries sort them.
+func bruteForce(points []Vec, removed map[int]bool, q Vec, m Metric) []Neighbor {
+	var all []Neighbor
+	for i, p := range points {
+		if !removed[i] {
+			all = append(all, Neighbor{Index: i, Point: p, Dist: m.Distance(q, p)})
+		}
+	}
+	slices.SortFunc(all, compareNeighbors)
+	return all
+}
+
+func sameNeighbors(t *testing.T, name string, got, expected []Neighbor) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("%s: got %d neighbors, want %d", name, len(got), len(expected))
+	}
+	for i := range got {
+		if got[i].Index != expected[i].Index || got[i].Dist != expected[i].Dist {
+			t.Fatalf("%s: neighbor %d was %d at %v, want %d at %v",
+				name, i, got[i].Index, got[i].Dist, expected[i].Index, expected[i].Dist)
+		}
+	}
+}
+
+func checkQueries(t *testing.T, name string, tree *KDTree, points []Vec, removed map[int]bool, r *rand.Rand, m Metric) {
+	t.Helper()
+	for range 20 {
+		q := randomVecs(r, 1, tree.Dim())[0]
+		all := bruteForce(points, removed, q, m)
+		for _, k := range []int{1, 5, 17} {
+			got, err := tree.KNearest(q, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameNeighbors(t, name+" KNearest", got, all[:min(k, len(all))])
+		}
+		// a radius that catches about a tenth of the points
+		radius := all[len(all)/10].Dist
+		got, _ := tree.Radius(q, radius)
+		within := slices.IndexFunc(all, func(n Neighbor) bool { return n.Dist > radius })
+		if within < 0 {
+			within = len(all)
+		}
+		sameNeighbors(t, name+" Radius", got, all[:within])
+	}
+}
+
+func TestKDTree(t *testing.T) {
+	metrics := []struct {
+		name string
+		m    Metric
+	}{
+		{"Euclidean", Euclidean{}},
+		{"SquaredEuclidean", SquaredEuclidean{}},
+		{"Manhattan", Manhattan{}},
+		{"Chebyshev", Chebyshev{}},
+		{"Minkowski", Minkowski{P: 3}},
+		{"Cosine", Cosine{}}, // no pruning: searches every point
+	}
+	r := rand.New(rand.NewPCG(4, 5))
+	for _, dim := range []int{1, 2, 3, 8} {
+		points := randomVecs(r, 2000, dim)
+		for _, tt := range metrics {
+			tree, err := KDTreeFrom(points, tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkQueries(t, tt.name, tree, points, nil, r, tt.m)
+		}
+	}
+}
+
+func TestKDTreeDuplicates(t *testing.T) {
+	// many equal coordinates end up on both sides of a split
+	r := rand.New(rand.NewPCG(1, 3))
+	points := make([]Vec, 500)
+	for i := range points {
+		points[i] = Vec{float64(r.IntN(4)), float64(r.IntN(4))}
+	}
+	tree, _ := KDTreeFrom(points, nil)
+	checkQueries(t, "duplicates", tree, points, nil, r, Euclidean{})
+	hamming, _ := KDTreeFrom(points, Hamming{})
+	checkQueries(t, "Hamming", hamming, points, nil, r, Hamming{})
+}
+
+func TestKDTreeInsertRemove(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 7))
+	points := randomVecs(r, 1000, 3)
+	tree, _ := KDTreeFrom(points, Manhattan{})
+	removed := map[int]bool{}
+
+	for _, p := range randomVecs(r, 500, 3) {
+		id, err := tree.Insert(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != len(points) {
+			t.Fatalf("Insert returned index %d, want %d", id, len(points))
+		}
+		points = append(points, p)
+	}
+	checkQueries(t, "after Insert", tree, points, removed, r, Manhattan{})
+
+	// removing more than half triggers a rebuild that must keep the indexes
+	for _, id := range r.Perm(len(points))[:900] {
+		if !tree.Remove(id) {
+			t.Fatalf("Remove(%d) failed", id)
+		}
+		removed[id] = true
+	}
+	if tree.Remove(-1) || tree.Remove(len(points)) {
+		t.Errorf("Remove of an unknown index succeeded")
+	}
+	for id := range removed {
+		if tree.Remove(id) {
+			t.Fatalf("Remove(%d) succeeded twice", id)
+		}
+		break
+	}
+	if tree.Len() != len(points)-900 {
+		t.Errorf("Len was %d, want %d", tree.Len(), len(points)-900)
+	}
+	checkQueries(t, "after Remove", tree, points, removed, r, Manhattan{})
+
+	tree.Rebuild()
+	checkQueries(t, "after Rebuild", tree, points, removed, r, Manhattan{})
+
+	// a tree grown only by insertion
+	grown := NewKDTree(2, nil)
+	if _, ok, _ := grown.Nearest(Vec{0, 0}); ok {
+		t.Errorf("Nearest found a point in an empty tree")
+	}
+	small := randomVecs(r, 300, 2)
+	for _, p := range small {
+		grown.Insert(p)
+	}
+	checkQueries(t, "grown", grown, small, nil, r, Euclidean{})
+	if n, ok, _ := grown.Nearest(small[42]); !ok || n.Index != 42 || n.Dist != 0 {
+		t.Errorf("Nearest was incorrect, got: %+v", n)
+	}
+}
+
+func TestKDTreeErrors(t *testing.T) {
+	if _, err := KDTreeFrom(nil, nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := KDTreeFrom([]Vec{{1, 2}, {3}}, nil); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	tree, _ := KDTreeFrom([]Vec{{1, 2}}, nil)
+	if _, err := tree.Insert(Vec{1}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := tree.KNearest(Vec{1, 2, 3}, 1); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := tree.Radius(Vec{1}, 1); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func BenchmarkNearestLinear(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	points := randomVecs(r, 50_000, 3)
+	q := Vec{1, 2, 3}
+	for b.Loop() {
+		best := 0
+		for i, p := range points {
+			if q.DistanceTo(p) < q.DistanceTo(points[best]) {
+				best = i
+			}
+		}
+		benchSink = float64(best)
+	}
+}
+
+func BenchmarkNearestKDTree(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 1))
+	tree, _ := KDTreeFrom(randomVecs(r, 50_000, 3), nil)
+	q := Vec{1, 2, 3}
+	for b.Loop() {
+		n, _, _ := tree.Nearest(q)
+		benchSink = float64(n.Index)
+	}
+}
@@ -0,0 +1,740 @@
+package vec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrShape is returned when matrix or vector dimensions do not match.
+	ErrShape = errors.New("vec: dimension mismatch")
+	// ErrSingular is returned when a matrix has no inverse.
+	ErrSingular = errors.New("vec: matrix is singular")
+)
+
+// singularTol is the pivot magnitude, relative to the largest entry of the
+// matrix, below which a matrix is considered singular.
+const singularTol = 1e-12
+
+// Mat is a dense row-major matrix of float64 values.
+//
+// Unlike Vec, operations that depend on shapes return an error instead of
+// panicking, so dimensions read from untrusted input can be checked.
+type Mat struct {
+	rows, cols int
+	data       []float64
+}
+
+// NewMat creates a rows x cols matrix from row-major data. The slice is used
+// directly, not copied.
+//
+// Returns:
+//   - ErrShape if len(data) != rows*cols or a dimension is negative.
+func NewMat(rows, cols int, data []float64) (*Mat, error) {
+	if rows < 0 || cols < 0 || len(data) != rows*cols {
+		return nil, fmt.Errorf("%w: %d values for a %dx%d matrix", ErrShape, len(data), rows, cols)
+	}
+	return &Mat{rows: rows, cols: cols, data: data}, nil
+}
+
+// ZeroMat creates a rows x cols matrix filled with zeros.
+func ZeroMat(rows, cols int) *Mat {
+	return &Mat{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+// Identity creates the n x n identity matrix.
+func Identity(n int) *Mat {
+	m := ZeroMat(n, n)
+	for i := range n {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// MatFromRows creates a matrix whose rows are copies of the given vectors.
+//
+// Returns:
+//   - ErrShape if the vectors do not all have the same length.
+func MatFromRows(rows ...Vec) (*Mat, error) {
+	if len(rows) == 0 {
+		return ZeroMat(0, 0), nil
+	}
+	cols := len(rows[0])
+	m := ZeroMat(len(rows), cols)
+	for i, r := range rows {
+		if len(r) != cols {
+			return nil, fmt.Errorf("%w: row %d has %d values, expected %d", ErrShape, i, len(r), cols)
+		}
+		copy(m.data[i*cols:], r)
+	}
+	return m, nil
+}
+
+// Rows returns the number of rows.
+func (m *Mat) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of columns.
+func (m *Mat) Cols() int {
+	return m.cols
+}
+
+// At returns the element at row i, column j. It panics if the indexes are
+// out of range.
+func (m *Mat) At(i, j int) float64 {
+	m.check(i, j)
+	return m.data[i*m.cols+j]
+}
+
+// Set sets the element at row i, column j. It panics if the indexes are out
+// of range.
+func (m *Mat) Set(i, j int, v float64) {
+	m.check(i, j)
+	m.data[i*m.cols+j] = v
+}
+
+func (m *Mat) check(i, j int) {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("vec: index (%d, %d) out of range for a %dx%d matrix", i, j, m.rows, m.cols))
+	}
+}
+
+// Row returns a copy of row i.
+func (m *Mat) Row(i int) Vec {
+	c := make(Vec, m.cols)
+	copy(c, m.data[i*m.cols:(i+1)*m.cols])
+	return c
+}
+
+// Col returns a copy of column j.
+func (m *Mat) Col(j int) Vec {
+	c := make(Vec, m.rows)
+	for i := range m.rows {
+		c[i] = m.data[i*m.cols+j]
+	}
+	return c
+}
+
+// Clone returns a deep copy of m.
+func (m *Mat) Clone() *Mat {
+	c := &Mat{rows: m.rows, cols: m.cols, data: make([]float64, len(m.data))}
+	copy(c.data, m.data)
+	return c
+}
+
+// Equals reports whether m and o have the same shape and every pair of
+// elements differs by at most eps.
+func (m *Mat) Equals(o *Mat, eps float64) bool {
+	if m.rows != o.rows || m.cols != o.cols {
+		return false
+	}
+	for i, v := range m.data {
+		if math.Abs(v-o.data[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+// T returns the transpose of m.
+func (m *Mat) T() *Mat {
+	t := ZeroMat(m.cols, m.rows)
+	for i := range m.rows {
+		for j := range m.cols {
+			t.data[j*m.rows+i] = m.data[i*m.cols+j]
+		}
+	}
+	return t
+}
+
+// Add returns m + o.
+//
+// Returns:
+//   - ErrShape if the matrices have different shapes.
+func (m *Mat) Add(o *Mat) (*Mat, error) {
+	if m.rows != o.rows || m.cols != o.cols {
+		return nil, fmt.Errorf("%w: cannot add %dx%d and %dx%d", ErrShape, m.rows, m.cols, o.rows, o.cols)
+	}
+	c := m.Clone()
+	for i, v := range o.data {
+		c.data[i] += v
+	}
+	return c, nil
+}
+
+// Sub returns m - o.
+//
+// Returns:
+//   - ErrShape if the matrices have different shapes.
+func (m *Mat) Sub(o *Mat) (*Mat, error) {
+	if m.rows != o.rows || m.cols != o.cols {
+		return nil, fmt.Errorf("%w: cannot subtract %dx%d and %dx%d", ErrShape, m.rows, m.cols, o.rows, o.cols)
+	}
+	c := m.Clone()
+	for i, v := range o.data {
+		c.data[i] -= v
+	}
+	return c, nil
+}
+
+// Scale returns m with every element multiplied by n.
+func (m *Mat) Scale(n float64) *Mat {
+	c := m.Clone()
+	for i := range c.data {
+		c.data[i] *= n
+	}
+	return c
+}
+
+// Mul returns the matrix product m × o.
+//
+// Returns:
+//   - ErrShape if m.Cols() != o.Rows().
+func (m *Mat) Mul(o *Mat) (*Mat, error) {
+	if m.cols != o.rows {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d by %dx%d", ErrShape, m.rows, m.cols, o.rows, o.cols)
+	}
+	c := ZeroMat(m.rows, o.cols)
+	for i := range m.rows {
+		row := c.data[i*o.cols : (i+1)*o.cols]
+		for k := range m.cols {
+			a := m.data[i*m.cols+k]
+			for j, b := range o.data[k*o.cols : (k+1)*o.cols] {
+				row[j] += a * b
+			}
+		}
+	}
+	return c, nil
+}
+
+// MulVec returns the product m × v, treating v as a column vector.
+//
+// Returns:
+//   - ErrShape if m.Cols() != len(v).
+func (m *Mat) MulVec(v Vec) (Vec, error) {
+	if m.cols != len(v) {
+		return nil, fmt.Errorf("%w: cannot multiply %dx%d by a vector of size %d", ErrShape, m.rows, m.cols, len(v))
+	}
+	c := make(Vec, m.rows)
+	for i := range m.rows {
+		acc := 0.0
+		for j, a := range m.data[i*m.cols : (i+1)*m.cols] {
+			acc += a * v[j]
+		}
+		c[i] = acc
+	}
+	return c, nil
+}
+
+func (m *Mat) maxAbs() float64 {
+	acc := 0.0
+	for _, v := range m.data {
+		acc = math.Max(acc, math.Abs(v))
+	}
+	return acc
+}
+
+// LU is the LU decomposition with partial pivoting P·A = L·U of a square
+// matrix, where L is unit lower triangular and U upper triangular.
+type LU struct {
+	lu       *Mat // L below the diagonal, U on and above it
+	piv      []int
+	sign     float64
+	singular bool
+}
+
+// LU computes the LU decomposition of m. A singular matrix still decomposes;
+// Solve and Inverse report ErrSingular for it.
+//
+// Returns:
+//   - ErrShape if m is not square.
+func (m *Mat) LU() (*LU, error) {
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("%w: LU requires a square matrix, got %dx%d", ErrShape, m.rows, m.cols)
+	}
+	n := m.rows
+	a := m.Clone()
+	piv := make([]int, n)
+	for i := range piv {
+		piv[i] = i
+	}
+	sign := 1.0
+	tol := singularTol * m.maxAbs()
+	singular := false
+
+	for k := range n {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.data[i*n+k]) > math.Abs(a.data[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := range n {
+				a.data[k*n+j], a.data[p*n+j] = a.data[p*n+j], a.data[k*n+j]
+			}
+			piv[k], piv[p] = piv[p], piv[k]
+			sign = -sign
+		}
+
+		pivot := a.data[k*n+k]
+		if math.Abs(pivot) <= tol {
+			singular = true
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			f := a.data[i*n+k] / pivot
+			a.data[i*n+k] = f
+			for j := k + 1; j < n; j++ {
+				a.data[i*n+j] -= f * a.data[k*n+j]
+			}
+		}
+	}
+	return &LU{lu: a, piv: piv, sign: sign, singular: singular}, nil
+}
+
+// L returns the unit lower triangular factor.
+func (d *LU) L() *Mat {
+	n := d.lu.rows
+	l := Identity(n)
+	for i := range n {
+		for j := range i {
+			l.data[i*n+j] = d.lu.data[i*n+j]
+		}
+	}
+	return l
+}
+
+// U returns the upper triangular factor.
+func (d *LU) U() *Mat {
+	n := d.lu.rows
+	u := ZeroMat(n, n)
+	for i := range n {
+		for j := i; j < n; j++ {
+			u.data[i*n+j] = d.lu.data[i*n+j]
+		}
+	}
+	return u
+}
+
+// P returns the permutation matrix, such that P·A = L·U.
+func (d *LU) P() *Mat {
+	n := d.lu.rows
+	p := ZeroMat(n, n)
+	for i, j := range d.piv {
+		p.data[i*n+j] = 1
+	}
+	return p
+}
+
+// Det returns the determinant of the decomposed matrix.
+func (d *LU) Det() float64 {
+	det := d.sign
+	n := d.lu.rows
+	for i := range n {
+		det *= d.lu.data[i*n+i]
+	}
+	return det
+}
+
+// Solve returns x such that A·x = b.
+//
+// Returns:
+//   - ErrShape if len(b) does not match the matrix size.
+//   - ErrSingular if the matrix is singular.
+func (d *LU) Solve(b Vec) (Vec, error) {
+	n := d.lu.rows
+	if len(b) != n {
+		return nil, fmt.Errorf("%w: expected a vector of size %d, got %d", ErrShape, n, len(b))
+	}
+	if d.singular {
+		return nil, ErrSingular
+	}
+
+	x := make(Vec, n)
+	for i, p := range d.piv {
+		x[i] = b[p]
+	}
+	// forward substitution with the unit lower triangle
+	for i := range n {
+		for j := range i {
+			x[i] -= d.lu.data[i*n+j] * x[j]
+		}
+	}
+	// back substitution with the upper triangle
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= d.lu.data[i*n+j] * x[j]
+		}
+		x[i] /= d.lu.data[i*n+i]
+	}
+	return x, nil
+}
+
+// Det returns the determinant of m.
+//
+// Returns:
+//   - ErrShape if m is not square.
+func (m *Mat) Det() (float64, error) {
+	d, err := m.LU()
+	if err != nil {
+		return 0, err
+	}
+	return d.Det(), nil
+}
+
+// Inverse returns the inverse of m.
+//
+// Returns:
+//   - ErrShape if m is not square.
+//   - ErrSingular if m has no inverse.
+func (m *Mat) Inverse() (*Mat, error) {
+	d, err := m.LU()
+	if err != nil {
+		return nil, err
+	}
+	if d.singular {
+		return nil, ErrSingular
+	}
+
+	n := m.rows
+	inv := ZeroMat(n, n)
+	e := make(Vec, n)
+	for j := range n {
+		clear(e)
+		e[j] = 1
+		col, _ := d.Solve(e)
+		for i, v := range col {
+			inv.data[i*n+j] = v
+		}
+	}
+	return inv, nil
+}
+
+// Solve returns x such that m·x = b for a square m.
+//
+// Returns:
+//   - ErrShape if m is not square or len(b) != m.Rows().
+//   - ErrSingular if m is singular.
+func (m *Mat) Solve(b Vec) (Vec, error) {
+	d, err := m.LU()
+	if err != nil {
+		return nil, err
+	}
+	return d.Solve(b)
+}
+
+// QR is the QR decomposition A = Q·R of an m x n matrix with m >= n, where
+// Q is m x n with orthonormal columns and R is n x n upper triangular.
+type QR struct {
+	qr    *Mat // Householder vectors below the diagonal, R above it
+	rdiag []float64
+}
+
+// QR computes the QR decomposition of m using Householder reflections.
+//
+// Returns:
+//   - ErrShape if m has fewer rows than columns.
+func (m *Mat) QR() (*QR, error) {
+	if m.rows < m.cols {
+		return nil, fmt.Errorf("%w: QR requires rows >= cols, got %dx%d", ErrShape, m.rows, m.cols)
+	}
+	a := m.Clone()
+	rows, cols := m.rows, m.cols
+	rdiag := make([]float64, cols)
+
+	for k := range cols {
+		norm := 0.0
+		for i := k; i < rows; i++ {
+			norm = math.Hypot(norm, a.data[i*cols+k])
+		}
+		if norm != 0 {
+			if a.data[k*cols+k] < 0 {
+				norm = -norm
+			}
+			for i := k; i < rows; i++ {
+				a.data[i*cols+k] /= norm
+			}
+			a.data[k*cols+k]++
+
+			for j := k + 1; j < cols; j++ {
+				s := 0.0
+				for i := k; i < rows; i++ {
+					s += a.data[i*cols+k] * a.data[i*cols+j]
+				}
+				s = -s / a.data[k*cols+k]
+				for i := k; i < rows; i++ {
+					a.data[i*cols+j] += s * a.data[i*cols+k]
+				}
+			}
+		}
+		rdiag[k] = -norm
+	}
+	return &QR{qr: a, rdiag: rdiag}, nil
+}
+
+// Q returns the m x n factor with orthonormal columns.
+func (d *QR) Q() *Mat {
+	rows, cols := d.qr.rows, d.qr.cols
+	q := ZeroMat(rows, cols)
+	for k := cols - 1; k >= 0; k-- {
+		q.data[k*cols+k] = 1
+		for j := k; j < cols; j++ {
+			if d.qr.data[k*cols+k] == 0 {
+				continue
+			}
+			s := 0.0
+			for i := k; i < rows; i++ {
+				s += d.qr.data[i*cols+k] * q.data[i*cols+j]
+			}
+			s = -s / d.qr.data[k*cols+k]
+			for i := k; i < rows; i++ {
+				q.data[i*cols+j] += s * d.qr.data[i*cols+k]
+			}
+		}
+	}
+	return q
+}
+
+// R returns the n x n upper triangular factor.
+func (d *QR) R() *Mat {
+	cols := d.qr.cols
+	r := ZeroMat(cols, cols)
+	for i := range cols {
+		r.data[i*cols+i] = d.rdiag[i]
+		for j := i + 1; j < cols; j++ {
+			r.data[i*cols+j] = d.qr.data[i*cols+j]
+		}
+	}
+	return r
+}
+
+// Solve returns the least squares solution x minimizing ||A·x - b||. For a
+// square A this is the exact solution of A·x = b.
+//
+// Returns:
+//   - ErrShape if len(b) != A.Rows().
+//   - ErrSingular if A does not have full column rank.
+func (d *QR) Solve(b Vec) (Vec, error) {
+	rows, cols := d.qr.rows, d.qr.cols
+	if len(b) != rows {
+		return nil, fmt.Errorf("%w: expected a vector of size %d, got %d", ErrShape, rows, len(b))
+	}
+	tol := 0.0
+	for _, r := range d.rdiag {
+		tol = math.Max(tol, math.Abs(r))
+	}
+	tol *= singularTol
+	for _, r := range d.rdiag {
+		if math.Abs(r) <= tol {
+			return nil, ErrSingular
+		}
+	}
+
+	y := make(Vec, rows)
+	copy(y, b)
+	// apply the Householder reflections: y = Qᵀ·b
+	for k := range cols {
+		s := 0.0
+		for i := k; i < rows; i++ {
+			s += d.qr.data[i*cols+k] * y[i]
+		}
+		s = -s / d.qr.data[k*cols+k]
+		for i := k; i < rows; i++ {
+			y[i] += s * d.qr.data[i*cols+k]
+		}
+	}
+	// back substitution with R
+	x := make(Vec, cols)
+	for k := cols - 1; k >= 0; k-- {
+		x[k] = y[k]
+		for j := k + 1; j < cols; j++ {
+			x[k] -= d.qr.data[k*cols+j] * x[j]
+		}
+		x[k] /= d.rdiag[k]
+	}
+	return x, nil
+}
+
+// Cholesky returns the lower triangular matrix L with a positive diagonal
+// such that m = L·Lᵀ, for a symmetric positive definite m such as a
+// covariance matrix. Only the lower triangle of m is read.
+//
+// Returns:
+//   - ErrShape if m is not square.
+//   - ErrSingular if m is not positive definite.
+func (m *Mat) Cholesky() (*Mat, error) {
+	if m.rows != m.cols {
+		return nil, fmt.Errorf("%w: Cholesky requires a square matrix, got %dx%d", ErrShape, m.rows, m.cols)
+	}
+	n := m.rows
+	l := ZeroMat(n, n)
+	tol := singularTol * m.maxAbs()
+	for j := range n {
+		s := m.data[j*n+j]
+		for k := range j {
+			s -= l.data[j*n+k] * l.data[j*n+k]
+		}
+		if s <= tol {
+			return nil, fmt.Errorf("%w: the matrix is not positive definite", ErrSingular)
+		}
+		d := math.Sqrt(s)
+		l.data[j*n+j] = d
+		for i := j + 1; i < n; i++ {
+			s := m.data[i*n+j]
+			for k := range j {
+				s -= l.data[i*n+k] * l.data[j*n+k]
+			}
+			l.data[i*n+j] = s / d
+		}
+	}
+	return l, nil
+}
+
+// String formats m with one row per line.
+func (m *Mat) String() string {
+	var sb strings.Builder
+	for i := range m.rows {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		fmt.Fprint(&sb, m.data[i*m.cols:(i+1)*m.cols])
+	}
+	return sb.String()
+}
+
+//
+// homogeneous transforms
+//
+
+// Translate2D returns the 3x3 homogeneous matrix translating by (tx, ty).
+func Translate2D(tx, ty float64) *Mat {
+	return &Mat{rows: 3, cols: 3, data: []float64{
+		1, 0, tx,
+		0, 1, ty,
+		0, 0, 1,
+	}}
+}
+
+// Rotate2D returns the 3x3 homogeneous matrix rotating counter-clockwise by
+// angle radians, the same rotation as Vec.Rot.
+func Rotate2D(angle float64) *Mat {
+	s, c := math.Sincos(angle)
+	return &Mat{rows: 3, cols: 3, data: []float64{
+		c, -s, 0,
+		s, c, 0,
+		0, 0, 1,
+	}}
+}
+
+// Scale2D returns the 3x3 homogeneous matrix scaling by (sx, sy).
+func Scale2D(sx, sy float64) *Mat {
+	return &Mat{rows: 3, cols: 3, data: []float64{
+		sx, 0, 0,
+		0, sy, 0,
+		0, 0, 1,
+	}}
+}
+
+// Translate3D returns the 4x4 homogeneous matrix translating by (tx, ty, tz).
+func Translate3D(tx, ty, tz float64) *Mat {
+	return &Mat{rows: 4, cols: 4, data: []float64{
+		1, 0, 0, tx,
+		0, 1, 0, ty,
+		0, 0, 1, tz,
+		0, 0, 0, 1,
+	}}
+}
+
+// Scale3D returns the 4x4 homogeneous matrix scaling by (sx, sy, sz).
+func Scale3D(sx, sy, sz float64) *Mat {
+	return &Mat{rows: 4, cols: 4, data: []float64{
+		sx, 0, 0, 0,
+		0, sy, 0, 0,
+		0, 0, sz, 0,
+		0, 0, 0, 1,
+	}}
+}
+
+// RotateX returns the 4x4 homogeneous matrix rotating by angle radians
+// around the x axis, following the right-hand rule.
+func RotateX(angle float64) *Mat {
+	s, c := math.Sincos(angle)
+	return &Mat{rows: 4, cols: 4, data: []float64{
+		1, 0, 0, 0,
+		0, c, -s, 0,
+		0, s, c, 0,
+		0, 0, 0, 1,
+	}}
+}
+
+// RotateY returns the 4x4 homogeneous matrix rotating by angle radians
+// around the y axis, following the right-hand rule.
+func RotateY(angle float64) *Mat {
+	s, c := math.Sincos(angle)
+	return &Mat{rows: 4, cols: 4, data: []float64{
+		c, 0, s, 0,
+		0, 1, 0, 0,
+		-s, 0, c, 0,
+		0, 0, 0, 1,
+	}}
+}
+
+// RotateZ returns the 4x4 homogeneous matrix rotating by angle radians
+// around the z axis, following the right-hand rule. On the xy plane it is
+// the same rotation as Vec.Rot.
+func RotateZ(angle float64) *Mat {
+	s, c := math.Sincos(angle)
+	return &Mat{rows: 4, cols: 4, data: []float64{
+		c, -s, 0, 0,
+		s, c, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}}
+}
+
+// TransformPoint applies the homogeneous transform m to the point p: p is
+// extended with w = 1, multiplied by m and projected back by dividing by w.
+//
+// Returns:
+//   - ErrShape if m is not (len(p)+1) x (len(p)+1).
+func (m *Mat) TransformPoint(p Vec) (Vec, error) {
+	r, err := m.transform(p, 1)
+	if err != nil {
+		return nil, err
+	}
+	w := r[len(p)]
+	r = r[:len(p)]
+	if w != 1 && w != 0 {
+		for i := range r {
+			r[i] /= w
+		}
+	}
+	return r, nil
+}
+
+// TransformDir applies the homogeneous transform m to the direction d,
+// extended with w = 0, so translations are ignored.
+//
+// Returns:
+//   - ErrShape if m is not (len(d)+1) x (len(d)+1).
+func (m *Mat) TransformDir(d Vec) (Vec, error) {
+	r, err := m.transform(d, 0)
+	if err != nil {
+		return nil, err
+	}
+	return r[:len(d)], nil
+}
+
+func (m *Mat) transform(v Vec, w float64) (Vec, error) {
+	if m.rows != len(v)+1 || m.cols != len(v)+1 {
+		return nil, fmt.Errorf("%w: a %dx%d transform cannot be applied to a vector of size %d", ErrShape, m.rows, m.cols, len(v))
+	}
+	h := make(Vec, len(v)+1)
+	copy(h, v)
+	h[len(v)] = w
+	return m.MulVec(h)
+}
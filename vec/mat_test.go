@@ -0,0 +1,282 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func vecNear(a, b Vec, eps float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+func mustMat(t *testing.T, rows, cols int, data ...float64) *Mat {
+	t.Helper()
+	m, err := NewMat(rows, cols, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMatMul(t *testing.T) {
+	a := mustMat(t, 2, 3, 1, 2, 3, 4, 5, 6)
+	b := mustMat(t, 3, 2, 7, 8, 9, 10, 11, 12)
+
+	got, err := a.Mul(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := mustMat(t, 2, 2, 58, 64, 139, 154); !got.Equals(expected, 0) {
+		t.Errorf("Mul was incorrect, got:\n%v\nwant:\n%v", got, expected)
+	}
+
+	// zeros follow IEEE arithmetic: 0·Inf and 0·NaN are NaN
+	zeros := mustMat(t, 1, 2, 0, 1)
+	special := mustMat(t, 2, 3, math.Inf(1), math.NaN(), 1, 3, 2, 4)
+	if got, _ := zeros.Mul(special); !math.IsNaN(got.At(0, 0)) || !math.IsNaN(got.At(0, 1)) || got.At(0, 2) != 4 {
+		t.Errorf("Mul with non-finite values was incorrect, got:\n%v", got)
+	}
+
+	v, err := a.MulVec(Vec{1, 0, -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vecNear(v, Vec{-2, -2}, 0) {
+		t.Errorf("MulVec was incorrect, got: %v", v)
+	}
+
+	if tr := a.T(); tr.Rows() != 3 || tr.At(2, 1) != 6 || tr.At(0, 1) != 4 {
+		t.Errorf("T was incorrect, got:\n%v", tr)
+	}
+
+	if _, err := a.Mul(a); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := a.MulVec(Vec{1, 2}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := NewMat(2, 2, []float64{1, 2, 3}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := MatFromRows(Vec{1, 2}, Vec{3}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func TestMatDetInverse(t *testing.T) {
+	tests := []struct {
+		m   *Mat
+		det float64
+	}{
+		{mustMat(t, 2, 2, 4, 7, 2, 6), 10},
+		{mustMat(t, 3, 3, 6, 1, 1, 4, -2, 5, 2, 8, 7), -306},
+		{mustMat(t, 3, 3, 0, 2, 1, 1, 0, 0, 0, 0, 3), -6},
+		{Identity(4), 1},
+	}
+	for _, tt := range tests {
+		det, err := tt.m.Det()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(det-tt.det) > 1e-9 {
+			t.Errorf("Det was incorrect, got: %v, want: %v", det, tt.det)
+		}
+
+		inv, err := tt.m.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := tt.m.Mul(inv)
+		if !p.Equals(Identity(tt.m.Rows()), 1e-12) {
+			t.Errorf("m × m⁻¹ is not the identity:\n%v", p)
+		}
+	}
+
+	singular := mustMat(t, 3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	if det, _ := singular.Det(); math.Abs(det) > 1e-9 {
+		t.Errorf("expected a zero determinant, got %v", det)
+	}
+	if _, err := singular.Inverse(); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+	if _, err := singular.Solve(Vec{1, 2, 3}); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+	if _, err := mustMat(t, 2, 3, 1, 2, 3, 4, 5, 6).Det(); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func TestMatLU(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for n := 1; n <= 6; n++ {
+		m := ZeroMat(n, n)
+		for i := range m.data {
+			m.data[i] = r.Float64()*2 - 1
+		}
+		d, err := m.LU()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pa, _ := d.P().Mul(m)
+		lu, _ := d.L().Mul(d.U())
+		if !pa.Equals(lu, 1e-12) {
+			t.Errorf("n=%d: P·A != L·U", n)
+		}
+
+		x := make(Vec, n)
+		for i := range x {
+			x[i] = float64(i + 1)
+		}
+		b, _ := m.MulVec(x)
+		got, err := m.Solve(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !vecNear(got, x, 1e-9) {
+			t.Errorf("n=%d: Solve was incorrect, got: %v, want: %v", n, got, x)
+		}
+	}
+}
+
+func TestMatQR(t *testing.T) {
+	m := mustMat(t, 4, 3,
+		12, -51, 4,
+		6, 167, -68,
+		-4, 24, -41,
+		1, 1, 1,
+	)
+	d, err := m.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, r := d.Q(), d.R()
+	qr, _ := q.Mul(r)
+	if !qr.Equals(m, 1e-9) {
+		t.Errorf("Q·R != A:\n%v", qr)
+	}
+	qtq, _ := q.T().Mul(q)
+	if !qtq.Equals(Identity(3), 1e-12) {
+		t.Errorf("Q does not have orthonormal columns:\n%v", qtq)
+	}
+	for i := range 3 {
+		for j := range i {
+			if r.At(i, j) != 0 {
+				t.Errorf("R is not upper triangular:\n%v", r)
+			}
+		}
+	}
+
+	// least squares fit of y = 1 + 2x through exact points
+	a := mustMat(t, 4, 2, 1, 0, 1, 1, 1, 2, 1, 3)
+	fit, _ := a.QR()
+	coef, err := fit.Solve(Vec{1, 3, 5, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vecNear(coef, Vec{1, 2}, 1e-12) {
+		t.Errorf("least squares was incorrect, got: %v", coef)
+	}
+
+	deficient, _ := mustMat(t, 3, 2, 1, 2, 2, 4, 3, 6).QR()
+	if _, err := deficient.Solve(Vec{1, 2, 3}); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+	if _, err := mustMat(t, 2, 3, 1, 2, 3, 4, 5, 6).QR(); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func TestMatCholesky(t *testing.T) {
+	m := mustMat(t, 3, 3,
+		4, 12, -16,
+		12, 37, -43,
+		-16, -43, 98,
+	)
+	l, err := m.Cholesky()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := mustMat(t, 3, 3,
+		2, 0, 0,
+		6, 1, 0,
+		-8, 5, 3,
+	)
+	if !l.Equals(expected, 1e-12) {
+		t.Errorf("Cholesky was incorrect, got:\n%v", l)
+	}
+	llt, _ := l.Mul(l.T())
+	if !llt.Equals(m, 1e-12) {
+		t.Errorf("L·Lᵀ != A:\n%v", llt)
+	}
+
+	indefinite := mustMat(t, 2, 2, 1, 2, 2, 1)
+	if _, err := indefinite.Cholesky(); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+	semidefinite := mustMat(t, 2, 2, 1, 1, 1, 1)
+	if _, err := semidefinite.Cholesky(); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+	if _, err := mustMat(t, 2, 3, 1, 2, 3, 4, 5, 6).Cholesky(); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func TestMatTransforms(t *testing.T) {
+	p := Vec{3, 4}
+	angle := math.Pi / 5
+
+	got, err := Rotate2D(angle).TransformPoint(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := p.Rot(angle); !vecNear(got, expected, 1e-12) {
+		t.Errorf("Rotate2D differs from Rot, got: %v, want: %v", got, expected)
+	}
+
+	// scale, then rotate a quarter turn, then translate
+	m, _ := Rotate2D(math.Pi / 2).Mul(Scale2D(2, 3))
+	m, _ = Translate2D(10, 20).Mul(m)
+	if got, _ := m.TransformPoint(Vec{1, 1}); !vecNear(got, Vec{7, 22}, 1e-12) {
+		t.Errorf("composed 2D transform was incorrect, got: %v", got)
+	}
+	if got, _ := m.TransformDir(Vec{1, 0}); !vecNear(got, Vec{0, 2}, 1e-12) {
+		t.Errorf("TransformDir must ignore translation, got: %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		m        *Mat
+		expected Vec
+	}{
+		{"translate", Translate3D(1, 2, 3), Vec{2, 3, 4}},
+		{"scale", Scale3D(2, 3, 4), Vec{2, 3, 4}},
+		{"rotate x", RotateX(math.Pi / 2), Vec{1, -1, 1}},
+		{"rotate y", RotateY(math.Pi / 2), Vec{1, 1, -1}},
+		{"rotate z", RotateZ(math.Pi / 2), Vec{-1, 1, 1}},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.TransformPoint(Vec{1, 1, 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !vecNear(got, tt.expected, 1e-12) {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, got, tt.expected)
+		}
+	}
+
+	if _, err := Translate3D(1, 2, 3).TransformPoint(Vec{1, 2}); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
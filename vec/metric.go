@@ -0,0 +1,275 @@
+package vec
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/AntonyChR/go-utils/assert"
+)
+
+//
+// norms
+//
+
+// NormL1 returns the L1 (taxicab) norm of v, the sum of the absolute values
+// of its elements.
+func (v *VecOf[T]) NormL1() float64 {
+	acc := 0.0
+	for _, e := range *v {
+		acc += math.Abs(float64(e))
+	}
+	return acc
+}
+
+// NormInf returns the L∞ (maximum) norm of v, the largest absolute value of
+// its elements, or 0 for an empty vector.
+func (v *VecOf[T]) NormInf() float64 {
+	acc := 0.0
+	for _, e := range *v {
+		acc = math.Max(acc, math.Abs(float64(e)))
+	}
+	return acc
+}
+
+// NormP returns the Lp norm of v, (Σ|v[i]|^p)^(1/p). NormP(1) is NormL1,
+// NormP(2) is Norm and NormP(+Inf) is NormInf. It panics if p < 1, for which
+// the formula is not a norm.
+func (v *VecOf[T]) NormP(p float64) float64 {
+	switch {
+	case p < 1 || math.IsNaN(p):
+		panic(fmt.Sprintf("vec: Lp norm requires p >= 1, got %v", p))
+	case p == 1:
+		return v.NormL1()
+	case p == 2:
+		return v.Norm()
+	case math.IsInf(p, 1):
+		return v.NormInf()
+	}
+	acc := 0.0
+	for _, e := range *v {
+		acc += math.Pow(math.Abs(float64(e)), p)
+	}
+	return math.Pow(acc, 1/p)
+}
+
+//
+// distances
+//
+
+// SquaredDistanceTo returns the squared Euclidean distance between v and u.
+// It avoids the square root, and orders points the same way as DistanceTo.
+func (v *VecOf[T]) SquaredDistanceTo(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	acc := 0.0
+	for i, e := range *v {
+		d := float64(e) - float64(u[i])
+		acc += d * d
+	}
+	return acc
+}
+
+// ManhattanTo returns the Manhattan (L1) distance between v and u.
+func (v *VecOf[T]) ManhattanTo(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	acc := 0.0
+	for i, e := range *v {
+		acc += math.Abs(float64(e) - float64(u[i]))
+	}
+	return acc
+}
+
+// ChebyshevTo returns the Chebyshev (L∞) distance between v and u, the
+// largest difference along any coordinate.
+func (v *VecOf[T]) ChebyshevTo(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	acc := 0.0
+	for i, e := range *v {
+		acc = math.Max(acc, math.Abs(float64(e)-float64(u[i])))
+	}
+	return acc
+}
+
+// MinkowskiTo returns the Minkowski distance of order p between v and u, the
+// Lp norm of their difference. It panics if p < 1.
+func (v *VecOf[T]) MinkowskiTo(u VecOf[T], p float64) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	switch {
+	case p < 1 || math.IsNaN(p):
+		panic(fmt.Sprintf("vec: Minkowski distance requires p >= 1, got %v", p))
+	case p == 1:
+		return v.ManhattanTo(u)
+	case p == 2:
+		return v.DistanceTo(u)
+	case math.IsInf(p, 1):
+		return v.ChebyshevTo(u)
+	}
+	acc := 0.0
+	for i, e := range *v {
+		acc += math.Pow(math.Abs(float64(e)-float64(u[i])), p)
+	}
+	return math.Pow(acc, 1/p)
+}
+
+// HammingTo returns the number of positions at which v and u differ.
+func (v *VecOf[T]) HammingTo(u VecOf[T]) int {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	n := 0
+	for i, e := range *v {
+		if e != u[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// CosineSimilarity returns the cosine of the angle between v and u, in
+// [-1, 1], or NaN if either vector is zero.
+func (v *VecOf[T]) CosineSimilarity(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	dot := 0.0
+	for i, e := range *v {
+		dot += float64(e) * float64(u[i])
+	}
+	n := v.Norm() * u.Norm()
+	if n == 0 {
+		return math.NaN()
+	}
+	return math.Max(-1, math.Min(1, dot/n))
+}
+
+// CosineDistance returns 1 - CosineSimilarity, in [0, 2]. It is not a
+// metric in the strict sense, as it does not satisfy the triangle
+// inequality, but is common for comparing directions such as embeddings.
+func (v *VecOf[T]) CosineDistance(u VecOf[T]) float64 {
+	return 1 - v.CosineSimilarity(u)
+}
+
+//
+// pluggable metrics
+//
+
+// Metric is a distance function between vectors, so that search and
+// clustering code can work with any notion of distance.
+type Metric interface {
+	// Distance returns the distance between a and b, which have the same
+	// size.
+	Distance(a, b Vec) float64
+}
+
+// MetricFunc adapts an ordinary function to the Metric interface.
+type MetricFunc func(a, b Vec) float64
+
+// Distance returns f(a, b).
+func (f MetricFunc) Distance(a, b Vec) float64 {
+	return f(a, b)
+}
+
+type (
+	// Euclidean is the Metric of Vec.DistanceTo.
+	Euclidean struct{}
+	// SquaredEuclidean is the Metric of Vec.SquaredDistanceTo.
+	SquaredEuclidean struct{}
+	// Manhattan is the Metric of Vec.ManhattanTo.
+	Manhattan struct{}
+	// Chebyshev is the Metric of Vec.ChebyshevTo.
+	Chebyshev struct{}
+	// Minkowski is the Metric of Vec.MinkowskiTo with order P >= 1.
+	Minkowski struct{ P float64 }
+	// Cosine is the Metric of Vec.CosineDistance.
+	Cosine struct{}
+	// Hamming is the Metric of Vec.HammingTo.
+	Hamming struct{}
+)
+
+func (Euclidean) Distance(a, b Vec) float64        { return a.DistanceTo(b) }
+func (SquaredEuclidean) Distance(a, b Vec) float64 { return a.SquaredDistanceTo(b) }
+func (Manhattan) Distance(a, b Vec) float64        { return a.ManhattanTo(b) }
+func (Chebyshev) Distance(a, b Vec) float64        { return a.ChebyshevTo(b) }
+func (m Minkowski) Distance(a, b Vec) float64      { return a.MinkowskiTo(b, m.P) }
+func (Cosine) Distance(a, b Vec) float64           { return a.CosineDistance(b) }
+func (Hamming) Distance(a, b Vec) float64          { return float64(a.HammingTo(b)) }
+
+// AxisBounder is implemented by metrics for which the distance between two
+// points is at least a function of their difference along any single
+// coordinate. Spatial indexes use it to skip whole regions of space; with a
+// metric that does not implement it they must compare against every point.
+type AxisBounder interface {
+	// AxisBound returns a lower bound of the distance between two points
+	// whose coordinates differ by diff >= 0 along some axis.
+	AxisBound(diff float64) float64
+}
+
+func (Euclidean) AxisBound(diff float64) float64        { return diff }
+func (SquaredEuclidean) AxisBound(diff float64) float64 { return diff * diff }
+func (Manhattan) AxisBound(diff float64) float64        { return diff }
+func (Chebyshev) AxisBound(diff float64) float64        { return diff }
+func (Minkowski) AxisBound(diff float64) float64        { return diff }
+
+func (Hamming) AxisBound(diff float64) float64 {
+	if diff == 0 {
+		return 0
+	}
+	return 1
+}
+
+// Mahalanobis is the Metric that measures distances in units of standard
+// deviation of a distribution with the given covariance, accounting for
+// correlations between coordinates. With the identity covariance it is the
+// Euclidean distance.
+type Mahalanobis struct {
+	chol *Mat // the Cholesky factor L of the covariance
+}
+
+// NewMahalanobis creates the Mahalanobis metric of a covariance matrix.
+//
+// Parameters:
+//   - cov: The n x n covariance matrix, symmetric and positive definite.
+//
+// Returns:
+//   - ErrShape if cov is not square.
+//   - ErrSingular if cov is not symmetric positive definite.
+func NewMahalanobis(cov *Mat) (*Mahalanobis, error) {
+	if cov.rows == cov.cols {
+		n, tol := cov.rows, singularTol*cov.maxAbs()
+		for i := range n {
+			for j := range i {
+				if math.Abs(cov.data[i*n+j]-cov.data[j*n+i]) > tol {
+					return nil, fmt.Errorf("%w: the covariance is not symmetric", ErrSingular)
+				}
+			}
+		}
+	}
+	chol, err := cov.Cholesky()
+	if err != nil {
+		return nil, err
+	}
+	return &Mahalanobis{chol: chol}, nil
+}
+
+// Distance returns sqrt((a-b)ᵀ·S⁻¹·(a-b)), where S is the covariance. It
+// panics if a and b do not have the dimension of the covariance matrix.
+func (m *Mahalanobis) Distance(a, b Vec) float64 {
+	n := m.chol.rows
+	assert.AssertEq(len(a), n, "Vectors must have the size of the covariance matrix")
+	assert.AssertEq(len(b), n, "Vectors must have the size of the covariance matrix")
+
+	// with S = L·Lᵀ the quadratic form is |y|² where L·y = a-b, solved by
+	// forward substitution; small dimensions stay on the stack
+	var buf [8]float64
+	y := buf[:0]
+	if n > len(buf) {
+		y = make([]float64, 0, n)
+	}
+	acc := 0.0
+	for i := range n {
+		row := m.chol.data[i*n : i*n+i]
+		s := a[i] - b[i]
+		for j, l := range row {
+			s -= l * y[j]
+		}
+		s /= m.chol.data[i*n+i]
+		y = append(y, s)
+		acc += s * s
+	}
+	return math.Sqrt(acc)
+}
@@ -0,0 +1,149 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNorms(t *testing.T) {
+	v := Vec{3, -4, 0}
+	tests := []struct {
+		name          string
+		got, expected float64
+	}{
+		{"NormL1", v.NormL1(), 7},
+		{"NormInf", v.NormInf(), 4},
+		{"NormP(1)", v.NormP(1), 7},
+		{"NormP(2)", v.NormP(2), 5},
+		{"NormP(3)", v.NormP(3), math.Cbrt(27 + 64)},
+		{"NormP(Inf)", v.NormP(math.Inf(1)), 4},
+		{"NormL1 int", (&VecOf[int]{-1, 2}).NormL1(), 3},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-12 {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	// Lp norms decrease towards L∞ as p grows
+	if !(v.NormP(1.5) > v.NormP(2) && v.NormP(10) > v.NormInf()) {
+		t.Errorf("Lp norms are not monotonic in p")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for p < 1")
+		}
+	}()
+	v.NormP(0.5)
+}
+
+func TestMetrics(t *testing.T) {
+	a, b := Vec{1, 2, 3}, Vec{4, 0, 3}
+	tests := []struct {
+		name     string
+		m        Metric
+		expected float64
+	}{
+		{"Euclidean", Euclidean{}, math.Sqrt(13)},
+		{"SquaredEuclidean", SquaredEuclidean{}, 13},
+		{"Manhattan", Manhattan{}, 5},
+		{"Chebyshev", Chebyshev{}, 3},
+		{"Minkowski 1", Minkowski{P: 1}, 5},
+		{"Minkowski 2", Minkowski{P: 2}, math.Sqrt(13)},
+		{"Minkowski 3", Minkowski{P: 3}, math.Cbrt(35)},
+		{"Cosine", Cosine{}, 1 - 13/(math.Sqrt(14)*5)},
+		{"Hamming", Hamming{}, 2},
+		{"MetricFunc", MetricFunc(func(a, b Vec) float64 { return a.ManhattanTo(b) * 2 }), 10},
+	}
+	for _, tt := range tests {
+		got := tt.m.Distance(a, b)
+		if math.Abs(got-tt.expected) > 1e-12 {
+			t.Errorf("%s was incorrect, got: %v, want: %v", tt.name, got, tt.expected)
+		}
+		if back := tt.m.Distance(b, a); math.Abs(back-got) > 1e-12 {
+			t.Errorf("%s is not symmetric: %v and %v", tt.name, got, back)
+		}
+		if self := tt.m.Distance(a, a); math.Abs(self) > 1e-12 {
+			t.Errorf("%s of a vector to itself was %v", tt.name, self)
+		}
+	}
+
+	x, y := Vec{1, 0}, Vec{-2, 0}
+	if got := x.CosineSimilarity(y); got != -1 {
+		t.Errorf("CosineSimilarity of opposite vectors was %v", got)
+	}
+	if got := x.CosineSimilarity(Zero(2)); !math.IsNaN(got) {
+		t.Errorf("CosineSimilarity with the zero vector must be NaN, got %v", got)
+	}
+}
+
+func TestMahalanobis(t *testing.T) {
+	identity, err := NewMahalanobis(Identity(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := Vec{1, 2}, Vec{4, 6}
+	if got := identity.Distance(a, b); math.Abs(got-5) > 1e-12 {
+		t.Errorf("Mahalanobis with identity covariance was %v, want 5", got)
+	}
+
+	// variances of 4 and 1: distances along x count half
+	cov, _ := NewMat(2, 2, []float64{4, 0, 0, 1})
+	m, _ := NewMahalanobis(cov)
+	if got := m.Distance(Vec{0, 0}, Vec{2, 0}); math.Abs(got-1) > 1e-12 {
+		t.Errorf("Mahalanobis along x was %v, want 1", got)
+	}
+	if got := m.Distance(Vec{0, 0}, Vec{0, 2}); math.Abs(got-2) > 1e-12 {
+		t.Errorf("Mahalanobis along y was %v, want 2", got)
+	}
+
+	// correlated coordinates: moving along the correlation is cheaper
+	cov, _ = NewMat(2, 2, []float64{1, 0.9, 0.9, 1})
+	m, _ = NewMahalanobis(cov)
+	along, across := m.Distance(Vec{0, 0}, Vec{1, 1}), m.Distance(Vec{0, 0}, Vec{1, -1})
+	if math.Abs(along-math.Sqrt(2/1.9)) > 1e-12 || math.Abs(across-math.Sqrt(2/0.1)) > 1e-9 {
+		t.Errorf("Mahalanobis with correlation was incorrect, got: %v, %v", along, across)
+	}
+
+	// agrees with the quadratic form of the inverse
+	cov, _ = NewMat(3, 3, []float64{4, 1, 0.5, 1, 3, -0.4, 0.5, -0.4, 2})
+	m, _ = NewMahalanobis(cov)
+	inv, _ := cov.Inverse()
+	p, q := Vec{1, -2, 0.5}, Vec{-1, 0.5, 3}
+	d := p.Sub(q)
+	sd, _ := inv.MulVec(d)
+	if got, want := m.Distance(p, q), math.Sqrt(d.Dot(sd)); math.Abs(got-want) > 1e-12 {
+		t.Errorf("Mahalanobis was %v, want %v", got, want)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { m.Distance(p, q) }); allocs != 0 {
+		t.Errorf("Distance allocated %v times", allocs)
+	}
+
+	for _, bad := range [][]float64{
+		{1, 1, 1, 1},     // singular
+		{1, 2, 2, 1},     // invertible but indefinite
+		{-1, 0, 0, -1},   // negative definite
+		{2, 1, -1, 2},    // not symmetric
+		{1, 0.5, 0.2, 1}, // not symmetric, positive definite lower triangle
+	} {
+		c, _ := NewMat(2, 2, bad)
+		if _, err := NewMahalanobis(c); !errors.Is(err, ErrSingular) {
+			t.Errorf("%v: expected ErrSingular, got %v", bad, err)
+		}
+	}
+	if _, err := NewMahalanobis(ZeroMat(2, 3)); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+}
+
+func TestDistanceToNoAlloc(t *testing.T) {
+	a, b := Vec{1, 2, 3}, Vec{4, 5, 6}
+	allocs := testing.AllocsPerRun(100, func() {
+		benchSink = a.DistanceTo(b) + a.ManhattanTo(b) + a.ChebyshevTo(b) + a.SquaredDistanceTo(b)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v per run", allocs)
+	}
+}
@@ -0,0 +1,339 @@
+package vec
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/AntonyChR/go-utils/assert"
+)
+
+// Quat is a quaternion W + Xi + Yj + Zk. Unit quaternions represent 3D
+// rotations; the methods that rotate assume q has been normalized.
+type Quat struct {
+	W, X, Y, Z float64
+}
+
+// QuatIdentity returns the quaternion of the identity rotation.
+func QuatIdentity() Quat {
+	return Quat{W: 1}
+}
+
+// QuatFromAxisAngle creates the unit quaternion rotating by angle radians
+// around axis, following the right-hand rule.
+//
+// Parameters:
+//   - axis: The 3D rotation axis; it does not need to be normalized.
+//   - angle: The angle in radians.
+//
+// Returns:
+//   - The rotation, or the identity if axis is the zero vector.
+func QuatFromAxisAngle(axis Vec, angle float64) Quat {
+	assert.AssertEq(axis.Len(), 3, ERROR_3D_METHOD)
+	n := axis.Norm()
+	if n == 0 {
+		return QuatIdentity()
+	}
+	s, c := math.Sincos(angle / 2)
+	s /= n
+	return Quat{W: c, X: axis[0] * s, Y: axis[1] * s, Z: axis[2] * s}
+}
+
+// AxisAngle returns the unit axis and the angle in [0, π] of the rotation
+// represented by q. For the identity the axis is (1, 0, 0).
+func (q Quat) AxisAngle() (Vec, float64) {
+	q = q.Unit()
+	if q.W < 0 {
+		q = q.Neg()
+	}
+	s := math.Sqrt(q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+	if s < 1e-15 {
+		return Vec{1, 0, 0}, 0
+	}
+	return Vec{q.X / s, q.Y / s, q.Z / s}, 2 * math.Atan2(s, q.W)
+}
+
+// EulerOrder is the order in which Euler angles are composed. The rotations
+// are intrinsic: EulerXYZ rotates around x, then around the rotated y, then
+// around the twice-rotated z, which is the matrix product Rx·Ry·Rz.
+type EulerOrder int
+
+const (
+	EulerXYZ EulerOrder = iota
+	EulerXZY
+	EulerYXZ
+	EulerYZX
+	EulerZXY
+	EulerZYX
+)
+
+func (o EulerOrder) String() string {
+	switch o {
+	case EulerXYZ:
+		return "XYZ"
+	case EulerXZY:
+		return "XZY"
+	case EulerYXZ:
+		return "YXZ"
+	case EulerYZX:
+		return "YZX"
+	case EulerZXY:
+		return "ZXY"
+	case EulerZYX:
+		return "ZYX"
+	}
+	return fmt.Sprintf("EulerOrder(%d)", int(o))
+}
+
+// axes returns the indexes of the first, second and third rotation axes,
+// and +1 if they are an even permutation of x, y, z or -1 otherwise.
+func (o EulerOrder) axes() (i, j, k int, parity float64) {
+	switch o {
+	case EulerXYZ:
+		return 0, 1, 2, 1
+	case EulerXZY:
+		return 0, 2, 1, -1
+	case EulerYXZ:
+		return 1, 0, 2, -1
+	case EulerYZX:
+		return 1, 2, 0, 1
+	case EulerZXY:
+		return 2, 0, 1, 1
+	case EulerZYX:
+		return 2, 1, 0, -1
+	}
+	panic(fmt.Sprintf("vec: invalid Euler order %d", int(o)))
+}
+
+func quatAroundAxis(axis int, angle float64) Quat {
+	s, c := math.Sincos(angle / 2)
+	q := Quat{W: c}
+	switch axis {
+	case 0:
+		q.X = s
+	case 1:
+		q.Y = s
+	default:
+		q.Z = s
+	}
+	return q
+}
+
+// QuatFromEuler creates a unit quaternion from Euler angles.
+//
+// Parameters:
+//   - x, y, z: The angles in radians around each axis.
+//   - order: The order in which the rotations are composed.
+//
+// Returns:
+//   - The combined rotation.
+func QuatFromEuler(x, y, z float64, order EulerOrder) Quat {
+	angles := [3]float64{x, y, z}
+	i, j, k, _ := order.axes()
+	return quatAroundAxis(i, angles[i]).
+		Mul(quatAroundAxis(j, angles[j])).
+		Mul(quatAroundAxis(k, angles[k]))
+}
+
+// Euler returns the Euler angles of q for the given order, so that
+// QuatFromEuler(q.Euler(order)) represents the same rotation. The angle of
+// the second axis is in [-π/2, π/2]; at ±π/2 (gimbal lock) the angle of the
+// third axis is reported as 0.
+func (q Quat) Euler(order EulerOrder) (x, y, z float64) {
+	r := q.rotation()
+	i, j, k, p := order.axes()
+
+	var angles [3]float64
+	sinB := math.Max(-1, math.Min(1, p*r[i][k]))
+	angles[j] = math.Asin(sinB)
+	if math.Abs(sinB) < 1-1e-12 {
+		angles[i] = math.Atan2(-p*r[j][k], r[k][k])
+		angles[k] = math.Atan2(-p*r[i][j], r[i][i])
+	} else {
+		angles[i] = math.Atan2(p*r[k][j], r[j][j])
+		angles[k] = 0
+	}
+	return angles[0], angles[1], angles[2]
+}
+
+// rotation returns the 3x3 rotation matrix of the normalized q.
+func (q Quat) rotation() [3][3]float64 {
+	q = q.Unit()
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return [3][3]float64{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y)},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x)},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y)},
+	}
+}
+
+// Mat returns the 3x3 rotation matrix of q.
+func (q Quat) Mat() *Mat {
+	r := q.rotation()
+	m := ZeroMat(3, 3)
+	for i := range 3 {
+		copy(m.data[i*3:], r[i][:])
+	}
+	return m
+}
+
+// Mat4 returns the 4x4 homogeneous rotation matrix of q, which composes with
+// Translate3D and Scale3D.
+func (q Quat) Mat4() *Mat {
+	r := q.rotation()
+	m := Identity(4)
+	for i := range 3 {
+		copy(m.data[i*4:], r[i][:])
+	}
+	return m
+}
+
+// QuatFromMat creates a unit quaternion from a rotation matrix, using the
+// numerically stable method of Shepperd.
+//
+// Parameters:
+//   - m: A 3x3 rotation matrix, or a 4x4 homogeneous matrix whose upper-left
+//     3x3 block is a rotation.
+//
+// Returns:
+//   - ErrShape if m is neither 3x3 nor 4x4.
+func QuatFromMat(m *Mat) (Quat, error) {
+	if m.rows != m.cols || (m.rows != 3 && m.rows != 4) {
+		return Quat{}, fmt.Errorf("%w: expected a 3x3 or 4x4 matrix, got %dx%d", ErrShape, m.rows, m.cols)
+	}
+	r := func(i, j int) float64 { return m.data[i*m.cols+j] }
+
+	var q Quat
+	switch tr := r(0, 0) + r(1, 1) + r(2, 2); {
+	case tr > 0:
+		s := 2 * math.Sqrt(tr+1)
+		q = Quat{W: s / 4, X: (r(2, 1) - r(1, 2)) / s, Y: (r(0, 2) - r(2, 0)) / s, Z: (r(1, 0) - r(0, 1)) / s}
+	case r(0, 0) > r(1, 1) && r(0, 0) > r(2, 2):
+		s := 2 * math.Sqrt(1+r(0, 0)-r(1, 1)-r(2, 2))
+		q = Quat{W: (r(2, 1) - r(1, 2)) / s, X: s / 4, Y: (r(0, 1) + r(1, 0)) / s, Z: (r(0, 2) + r(2, 0)) / s}
+	case r(1, 1) > r(2, 2):
+		s := 2 * math.Sqrt(1+r(1, 1)-r(0, 0)-r(2, 2))
+		q = Quat{W: (r(0, 2) - r(2, 0)) / s, X: (r(0, 1) + r(1, 0)) / s, Y: s / 4, Z: (r(1, 2) + r(2, 1)) / s}
+	default:
+		s := 2 * math.Sqrt(1+r(2, 2)-r(0, 0)-r(1, 1))
+		q = Quat{W: (r(1, 0) - r(0, 1)) / s, X: (r(0, 2) + r(2, 0)) / s, Y: (r(1, 2) + r(2, 1)) / s, Z: s / 4}
+	}
+	return q.Unit(), nil
+}
+
+// Mul returns the Hamilton product q·r, the rotation r followed by q.
+func (q Quat) Mul(r Quat) Quat {
+	return Quat{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// Neg returns -q, which represents the same rotation as q.
+func (q Quat) Neg() Quat {
+	return Quat{-q.W, -q.X, -q.Y, -q.Z}
+}
+
+// Conj returns the conjugate of q. For a unit quaternion it is the inverse
+// rotation.
+func (q Quat) Conj() Quat {
+	return Quat{q.W, -q.X, -q.Y, -q.Z}
+}
+
+// Inverse returns the multiplicative inverse of q, or the zero quaternion if
+// q is zero.
+func (q Quat) Inverse() Quat {
+	n := q.Dot(q)
+	if n == 0 {
+		return Quat{}
+	}
+	c := q.Conj()
+	return Quat{c.W / n, c.X / n, c.Y / n, c.Z / n}
+}
+
+// Dot returns the dot product of q and r as 4D vectors.
+func (q Quat) Dot(r Quat) float64 {
+	return q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+}
+
+// Norm returns the magnitude of q.
+func (q Quat) Norm() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Unit returns q scaled to magnitude 1, or the identity if q is zero.
+func (q Quat) Unit() Quat {
+	n := q.Norm()
+	if n == 0 {
+		return QuatIdentity()
+	}
+	return Quat{q.W / n, q.X / n, q.Y / n, q.Z / n}
+}
+
+// Rotate rotates the 3D vector v by the unit quaternion q.
+//
+// Parameters:
+//   - v: The 3D vector to rotate.
+//
+// Returns:
+//   - The rotated vector as a new 3D vector.
+func (q Quat) Rotate(v Vec) Vec {
+	assert.AssertEq(v.Len(), 3, ERROR_3D_METHOD)
+	// v' = v + 2w(u×v) + 2u×(u×v), with u the vector part of q
+	u := Vec{q.X, q.Y, q.Z}
+	t := u.Prod3d(v)
+	t = t.Scale(2)
+	c := u.Prod3d(t)
+	return Vec{
+		v[0] + q.W*t[0] + c[0],
+		v[1] + q.W*t[1] + c[1],
+		v[2] + q.W*t[2] + c[2],
+	}
+}
+
+// Nlerp interpolates between the rotations q and r by normalizing the linear
+// interpolation of their components. It takes the shortest path and is
+// cheaper than Slerp, but the angular speed is not constant.
+func Nlerp(q, r Quat, t float64) Quat {
+	if q.Dot(r) < 0 {
+		r = r.Neg()
+	}
+	return Quat{
+		q.W + (r.W-q.W)*t,
+		q.X + (r.X-q.X)*t,
+		q.Y + (r.Y-q.Y)*t,
+		q.Z + (r.Z-q.Z)*t,
+	}.Unit()
+}
+
+// Slerp interpolates between the unit quaternions q and r along the shortest
+// great arc, at constant angular speed.
+//
+// Parameters:
+//   - q: The rotation at t = 0.
+//   - r: The rotation at t = 1.
+//   - t: The interpolation parameter, usually in [0, 1].
+//
+// Returns:
+//   - The interpolated unit quaternion.
+func Slerp(q, r Quat, t float64) Quat {
+	d := q.Dot(r)
+	if d < 0 {
+		r, d = r.Neg(), -d
+	}
+	// nearly identical rotations: sin(θ) vanishes, fall back to Nlerp
+	if d > 1-1e-9 {
+		return Nlerp(q, r, t)
+	}
+	theta := math.Acos(math.Min(d, 1))
+	sin := math.Sin(theta)
+	a := math.Sin((1-t)*theta) / sin
+	b := math.Sin(t*theta) / sin
+	return Quat{
+		a*q.W + b*r.W,
+		a*q.X + b*r.X,
+		a*q.Y + b*r.Y,
+		a*q.Z + b*r.Z,
+	}
+}
@@ -0,0 +1,153 @@
+package vec
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// sameRotation reports whether q and r represent the same rotation; q and -q
+// are equivalent.
+func sameRotation(q, r Quat, eps float64) bool {
+	return math.Abs(math.Abs(q.Unit().Dot(r.Unit()))-1) < eps
+}
+
+func TestQuatRotate(t *testing.T) {
+	tests := []struct {
+		axis     Vec
+		angle    float64
+		v        Vec
+		expected Vec
+	}{
+		{Vec{0, 0, 1}, math.Pi / 2, Vec{1, 0, 0}, Vec{0, 1, 0}},
+		{Vec{1, 0, 0}, math.Pi / 2, Vec{0, 1, 0}, Vec{0, 0, 1}},
+		{Vec{0, 2, 0}, math.Pi / 2, Vec{0, 0, 1}, Vec{1, 0, 0}},
+		{Vec{1, 1, 1}, 2 * math.Pi / 3, Vec{1, 0, 0}, Vec{0, 1, 0}},
+		{Vec{0, 0, 1}, math.Pi, Vec{1, 2, 3}, Vec{-1, -2, 3}},
+		{Vec{0, 0, 0}, 1, Vec{1, 2, 3}, Vec{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		q := QuatFromAxisAngle(tt.axis, tt.angle)
+		if got := q.Rotate(tt.v); !vecNear(got, tt.expected, 1e-12) {
+			t.Errorf("Rotate(%v) around %v was incorrect, got: %v, want: %v", tt.v, tt.axis, got, tt.expected)
+		}
+		if got := tt.v.RotAroundAxis(tt.axis, tt.angle); !vecNear(got, tt.expected, 1e-12) {
+			t.Errorf("RotAroundAxis(%v) around %v was incorrect, got: %v, want: %v", tt.v, tt.axis, got, tt.expected)
+		}
+		m, _ := q.Mat().MulVec(tt.v)
+		if !vecNear(m, tt.expected, 1e-12) {
+			t.Errorf("Mat of rotation around %v was incorrect, got: %v, want: %v", tt.axis, m, tt.expected)
+		}
+	}
+
+	// RotateZ and the quaternion agree on the xy plane with Rot
+	p := Vec{3, 4}
+	q := QuatFromAxisAngle(Vec{0, 0, 1}, 0.7)
+	got := q.Rotate(Vec{3, 4, 0})
+	if expected := p.Rot(0.7); !vecNear(got[:2], expected, 1e-12) {
+		t.Errorf("rotation around z differs from Rot, got: %v, want: %v", got, expected)
+	}
+}
+
+func TestQuatAlgebra(t *testing.T) {
+	a := QuatFromAxisAngle(Vec{1, 2, 3}, 0.4)
+	b := QuatFromAxisAngle(Vec{-1, 0, 2}, 1.1)
+	v := Vec{0.5, -1, 2}
+
+	// a·b rotates by b first, then by a
+	got := a.Mul(b).Rotate(v)
+	expected := a.Rotate(b.Rotate(v))
+	if !vecNear(got, expected, 1e-12) {
+		t.Errorf("Mul was incorrect, got: %v, want: %v", got, expected)
+	}
+	if id := a.Mul(a.Inverse()); !sameRotation(id, QuatIdentity(), 1e-12) {
+		t.Errorf("q·q⁻¹ is not the identity: %v", id)
+	}
+	if !sameRotation(a.Conj(), a.Inverse(), 1e-12) {
+		t.Errorf("the conjugate of a unit quaternion must be its inverse")
+	}
+	if n := (Quat{1, 2, 3, 4}).Unit().Norm(); math.Abs(n-1) > 1e-15 {
+		t.Errorf("Unit was incorrect, got norm %v", n)
+	}
+
+	axis, angle := QuatFromAxisAngle(Vec{0, 3, 4}, 2.5).AxisAngle()
+	if !vecNear(axis, Vec{0, 0.6, 0.8}, 1e-12) || math.Abs(angle-2.5) > 1e-12 {
+		t.Errorf("AxisAngle was incorrect, got: %v, %v", axis, angle)
+	}
+
+	m := a.Mul(b).Mat4()
+	fromMat, err := QuatFromMat(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameRotation(fromMat, a.Mul(b), 1e-12) {
+		t.Errorf("QuatFromMat was incorrect, got: %v, want: %v", fromMat, a.Mul(b))
+	}
+	// exercise every branch of Shepperd's method with half turns
+	for _, axis := range []Vec{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		q := QuatFromAxisAngle(axis, math.Pi)
+		if got, _ := QuatFromMat(q.Mat()); !sameRotation(got, q, 1e-12) {
+			t.Errorf("QuatFromMat of a half turn around %v was incorrect, got: %v", axis, got)
+		}
+	}
+	if _, err := QuatFromMat(Identity(2)); err == nil {
+		t.Errorf("expected an error for a 2x2 matrix")
+	}
+}
+
+func TestQuatEuler(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 8))
+	orders := []EulerOrder{EulerXYZ, EulerXZY, EulerYXZ, EulerYZX, EulerZXY, EulerZYX}
+
+	// EulerXYZ is the matrix product Rx·Ry·Rz
+	q := QuatFromEuler(0.3, -0.5, 1.2, EulerXYZ)
+	rxyz, _ := RotateX(0.3).Mul(RotateY(-0.5))
+	rxyz, _ = rxyz.Mul(RotateZ(1.2))
+	if !q.Mat4().Equals(rxyz, 1e-12) {
+		t.Errorf("EulerXYZ does not match Rx·Ry·Rz:\n%v", q.Mat4())
+	}
+
+	for _, order := range orders {
+		for range 200 {
+			x, y, z := r.Float64()*2*math.Pi-math.Pi, r.Float64()*math.Pi-math.Pi/2, r.Float64()*2*math.Pi-math.Pi
+			q := QuatFromEuler(x, y, z, order)
+			gx, gy, gz := q.Euler(order)
+			if !sameRotation(QuatFromEuler(gx, gy, gz, order), q, 1e-9) {
+				t.Errorf("%v: round trip of (%v, %v, %v) gave (%v, %v, %v)", order, x, y, z, gx, gy, gz)
+			}
+		}
+		// gimbal lock on the middle axis
+		for _, b := range []float64{math.Pi / 2, -math.Pi / 2} {
+			angles := [3]float64{0.4, 0.4, 0.4}
+			i, j, k, _ := order.axes()
+			angles[i], angles[j], angles[k] = 0.7, b, -0.2
+			q := QuatFromEuler(angles[0], angles[1], angles[2], order)
+			gx, gy, gz := q.Euler(order)
+			if !sameRotation(QuatFromEuler(gx, gy, gz, order), q, 1e-9) {
+				t.Errorf("%v: gimbal lock round trip gave (%v, %v, %v)", order, gx, gy, gz)
+			}
+		}
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	a := QuatIdentity()
+	b := QuatFromAxisAngle(Vec{0, 0, 1}, math.Pi/2)
+
+	for _, tt := range []float64{0, 0.25, 0.5, 1} {
+		expected := QuatFromAxisAngle(Vec{0, 0, 1}, tt*math.Pi/2)
+		if got := Slerp(a, b, tt); !sameRotation(got, expected, 1e-12) {
+			t.Errorf("Slerp(t=%v) was incorrect, got: %v, want: %v", tt, got, expected)
+		}
+	}
+	// -b is the same rotation; the shortest path must still be taken
+	if got := Slerp(a, b.Neg(), 0.5); !sameRotation(got, QuatFromAxisAngle(Vec{0, 0, 1}, math.Pi/4), 1e-12) {
+		t.Errorf("Slerp did not take the shortest path, got: %v", got)
+	}
+	if got := Nlerp(a, b, 0.5); !sameRotation(got, QuatFromAxisAngle(Vec{0, 0, 1}, math.Pi/4), 1e-12) {
+		t.Errorf("Nlerp midpoint was incorrect, got: %v", got)
+	}
+	if got := Slerp(b, b, 0.3); !sameRotation(got, b, 1e-12) {
+		t.Errorf("Slerp of identical rotations was incorrect, got: %v", got)
+	}
+}
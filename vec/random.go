@@ -0,0 +1,160 @@
+package vec
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/AntonyChR/go-utils/assert"
+)
+
+// The functions of this file draw from the *rand.Rand they are given, so a
+// source seeded the same way, such as rand.New(rand.NewPCG(1, 2)), gives the
+// same values on every run, and goroutines with their own source do not
+// contend for the global one. A *rand.Rand is not safe for concurrent use.
+
+// globalSource draws from the global source of math/rand/v2, which is safe
+// for concurrent use.
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// globalRand backs the convenience functions that take no source.
+var globalRand = rand.New(globalSource{})
+
+// RandUniform returns a vector of size values drawn uniformly from [lo, hi).
+func RandUniform(r *rand.Rand, size int, lo, hi float64) Vec {
+	v := make(Vec, size)
+	for i := range v {
+		v[i] = lo + (hi-lo)*r.Float64()
+	}
+	return v
+}
+
+// RandBox returns a point drawn uniformly from the box [lo[i], hi[i]) of
+// each coordinate. It panics if lo and hi have different sizes.
+func RandBox(r *rand.Rand, lo, hi Vec) Vec {
+	assert.AssertEq(len(hi), len(lo), "Vectors must have the same size")
+	v := make(Vec, len(lo))
+	for i := range v {
+		v[i] = lo[i] + (hi[i]-lo[i])*r.Float64()
+	}
+	return v
+}
+
+// RandNormal returns a vector of size independent values from the standard
+// normal distribution.
+func RandNormal(r *rand.Rand, size int) Vec {
+	v := make(Vec, size)
+	for i := range v {
+		v[i] = r.NormFloat64()
+	}
+	return v
+}
+
+// RandOnSphere returns a point drawn uniformly from the surface of the unit
+// sphere in dim dimensions: the unit circle for dim 2, the usual sphere for
+// dim 3. It panics if dim < 1.
+func RandOnSphere(r *rand.Rand, dim int) Vec {
+	if dim < 1 {
+		panic(fmt.Sprintf("vec: sphere of dimension %d", dim))
+	}
+	// the standard normal distribution is the same in every direction
+	for {
+		v := RandNormal(r, dim)
+		if n := v.Norm(); n > 0 {
+			v.ScaleInPlace(1 / n)
+			return v
+		}
+	}
+}
+
+// RandInBall returns a point drawn uniformly from the unit ball in dim
+// dimensions. It panics if dim < 1.
+func RandInBall(r *rand.Rand, dim int) Vec {
+	v := RandOnSphere(r, dim)
+	// the volume within radius s grows as s^dim
+	v.ScaleInPlace(math.Pow(r.Float64(), 1/float64(dim)))
+	return v
+}
+
+// RandInPolygon returns a point drawn uniformly from the inside of p. It
+// samples the bounding box of p until a point falls inside, which takes
+// about as many tries as the box is larger than p. It panics if p has no
+// area.
+func RandInPolygon(r *rand.Rand, p Polygon) Vec2 {
+	if p.Area() == 0 {
+		panic("vec: random point in a polygon without area")
+	}
+	box := p.AABB()
+	for {
+		q := Vec2{
+			box.Min[0] + (box.Max[0]-box.Min[0])*r.Float64(),
+			box.Min[1] + (box.Max[1]-box.Min[1])*r.Float64(),
+		}
+		if p.Contains(q) {
+			return q
+		}
+	}
+}
+
+// Gaussian is a multivariate normal distribution.
+type Gaussian struct {
+	mean Vec
+	chol *Mat // the Cholesky factor of the covariance
+}
+
+// NewGaussian creates the multivariate normal distribution with the given
+// mean and covariance matrix.
+//
+// Parameters:
+//   - mean: The mean.
+//   - cov: The covariance, symmetric positive definite, of size
+//     len(mean) x len(mean).
+//
+// Returns:
+//   - ErrShape if cov does not match the size of mean.
+//   - ErrSingular if cov is not positive definite.
+func NewGaussian(mean Vec, cov *Mat) (*Gaussian, error) {
+	if cov.rows != len(mean) || cov.cols != len(mean) {
+		return nil, fmt.Errorf("%w: %dx%d covariance for a mean of size %d", ErrShape, cov.rows, cov.cols, len(mean))
+	}
+	chol, err := cov.Cholesky()
+	if err != nil {
+		return nil, err
+	}
+	return &Gaussian{mean: slices.Clone(mean), chol: chol}, nil
+}
+
+// Dim returns the dimension of the distribution.
+func (g *Gaussian) Dim() int {
+	return len(g.mean)
+}
+
+// Sample returns a point drawn from g.
+func (g *Gaussian) Sample(r *rand.Rand) Vec {
+	return g.SampleTo(r, make(Vec, len(g.mean)))
+}
+
+// SampleTo draws a point from g into dst without allocating, and returns
+// dst. It panics if dst does not have the dimension of g.
+func (g *Gaussian) SampleTo(r *rand.Rand, dst Vec) Vec {
+	n := len(g.mean)
+	assert.AssertEq(len(dst), n, "Vectors must have the same size")
+	for i := range dst {
+		dst[i] = r.NormFloat64()
+	}
+	// dst = mean + L·z, from the last row up so that each row only reads
+	// values of z not yet overwritten
+	for i := n - 1; i >= 0; i-- {
+		s := g.mean[i]
+		for j, l := range g.chol.data[i*n : i*n+i+1] {
+			s += l * dst[j]
+		}
+		dst[i] = s
+	}
+	return dst
+}
@@ -0,0 +1,180 @@
+package vec
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestRandReproducible(t *testing.T) {
+	draw := func() []Vec {
+		r := rand.New(rand.NewPCG(10, 20))
+		g, _ := NewGaussian(Vec{1, 2}, Identity(2))
+		return []Vec{
+			RandUniform(r, 3, -1, 1),
+			RandBox(r, Vec{0, 10}, Vec{1, 20}),
+			RandNormal(r, 3),
+			RandOnSphere(r, 3),
+			RandInBall(r, 4),
+			RandInPolygon(r, Polygon{{0, 0}, {1, 0}, {0, 1}}).Vec(),
+			g.Sample(r),
+		}
+	}
+	a, b := draw(), draw()
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			t.Errorf("draw %d differs with the same seed: %v and %v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestRandUniform(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 1))
+	v := RandUniform(r, 10000, -3, 5)
+	sum := 0.0
+	for _, x := range v {
+		if x < -3 || x >= 5 {
+			t.Fatalf("%v is outside [-3, 5)", x)
+		}
+		sum += x
+	}
+	if mean := sum / float64(len(v)); math.Abs(mean-1) > 0.1 {
+		t.Errorf("the mean was %v, want 1", mean)
+	}
+
+	lo, hi := Vec{0, 100, -1}, Vec{1, 101, -1}
+	for range 100 {
+		p := RandBox(r, lo, hi)
+		for i := range p {
+			if p[i] < lo[i] || p[i] > hi[i] {
+				t.Fatalf("%v is outside the box", p)
+			}
+		}
+	}
+
+	// the convenience wrapper keeps its behavior
+	if v := Rand(5); len(v) != 5 || v.NormInf() >= 1 {
+		t.Errorf("Rand was incorrect, got: %v", v)
+	}
+}
+
+func TestGaussian(t *testing.T) {
+	mean := Vec{1, -2}
+	cov := mustMat(t, 2, 2, 4, 1.2, 1.2, 1)
+	g, err := NewGaussian(mean, cov)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewPCG(5, 6))
+	const n = 50000
+	samples := make([]Vec, n)
+	sum := make(Vec, 2)
+	for i := range samples {
+		samples[i] = g.Sample(r)
+		sum.AddInPlace(samples[i])
+	}
+	sum.ScaleInPlace(1.0 / n)
+	if !vecNear(sum, mean, 0.03) {
+		t.Errorf("the sample mean was %v, want %v", sum, mean)
+	}
+	got := ZeroMat(2, 2)
+	for _, s := range samples {
+		for i := range 2 {
+			for j := range 2 {
+				got.Set(i, j, got.At(i, j)+(s[i]-sum[i])*(s[j]-sum[j])/n)
+			}
+		}
+	}
+	if !got.Equals(cov, 0.06) {
+		t.Errorf("the sample covariance was:\n%v", got)
+	}
+
+	dst := make(Vec, 2)
+	if allocs := testing.AllocsPerRun(100, func() { g.SampleTo(r, dst) }); allocs != 0 {
+		t.Errorf("SampleTo allocated %v times", allocs)
+	}
+
+	if _, err := NewGaussian(Vec{1}, cov); !errors.Is(err, ErrShape) {
+		t.Errorf("expected ErrShape, got %v", err)
+	}
+	if _, err := NewGaussian(mean, mustMat(t, 2, 2, 1, 2, 2, 1)); !errors.Is(err, ErrSingular) {
+		t.Errorf("expected ErrSingular, got %v", err)
+	}
+}
+
+func TestRandSphereAndBall(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 3))
+	for _, dim := range []int{1, 2, 3, 5} {
+		const n = 20000
+		sum := make(Vec, dim)
+		inner := 0
+		for range n {
+			s := RandOnSphere(r, dim)
+			if math.Abs(s.Norm()-1) > 1e-12 {
+				t.Fatalf("dim %d: a point on the sphere had norm %v", dim, s.Norm())
+			}
+			sum.AddInPlace(s)
+
+			b := RandInBall(r, dim)
+			if b.Norm() > 1 {
+				t.Fatalf("dim %d: a point in the ball had norm %v", dim, b.Norm())
+			}
+			if b.Norm() < 0.5 {
+				inner++
+			}
+		}
+		// uniform on the sphere: no preferred direction
+		if sum.Norm()/n > 0.02 {
+			t.Errorf("dim %d: the mean on the sphere was %v", dim, sum.Scale(1.0/n))
+		}
+		// uniform in the ball: the inner ball of radius 1/2 gets 2^-dim
+		if got, want := float64(inner)/n, math.Pow(0.5, float64(dim)); math.Abs(got-want) > 0.015 {
+			t.Errorf("dim %d: %v of the points in the inner ball, want %v", dim, got, want)
+		}
+	}
+}
+
+func TestRandInPolygon(t *testing.T) {
+	r := rand.New(rand.NewPCG(8, 1))
+	// an L shape: the bar [0,4]x[0,1] holds 4 of its 6 units of area
+	l := Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 3}, {0, 3}}
+	const n = 30000
+	bar := 0
+	for range n {
+		p := RandInPolygon(r, l)
+		if !l.Contains(p) {
+			t.Fatalf("%v is outside the polygon", p)
+		}
+		if p[1] < 1 {
+			bar++
+		}
+	}
+	if got := float64(bar) / n; math.Abs(got-4.0/6) > 0.015 {
+		t.Errorf("%v of the points in the bar, want %v", got, 4.0/6)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for a polygon without area")
+		}
+	}()
+	RandInPolygon(r, Polygon{{0, 0}, {1, 1}, {2, 2}})
+}
+
+func BenchmarkRandGlobal(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Rand(3)
+		}
+	})
+}
+
+func BenchmarkRandSeeded(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+		for pb.Next() {
+			RandUniform(r, 3, 0, 1)
+		}
+	})
+}
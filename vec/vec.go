@@ -3,95 +3,130 @@ package vec
 
 import (
 	"math"
-	rand "math/rand"
 
 	"github.com/AntonyChR/go-utils/assert"
+	gmath "github.com/AntonyChR/go-utils/math"
 )
 
+// VecOf represents a vector of any integer or floating-point element type,
+// such as float32 for graphics or int for grid coordinates.
+//
+// Operations whose result is not an integer in general, like Unit, Rot or
+// PowScalar, are computed in float64; for integer element types the results
+// are rounded to the nearest integer. Magnitudes such as Norm and
+// DistanceTo are always returned as float64. Integer arithmetic follows Go:
+// Div truncates and overflow wraps around.
+type VecOf[T gmath.Number] []T
+
 // Vec represents a vector of float64 values.
-type Vec []float64
+type Vec = VecOf[float64]
+
+// isInteger reports whether T is an integer type.
+func isInteger[T gmath.Number]() bool {
+	half := 0.5
+	return T(half) == 0
+}
 
-func (v *Vec) Equals(b Vec) bool {
+// fromFloat converts x to T, rounding to the nearest integer if integer is
+// true. Callers compute the flag once with isInteger.
+func fromFloat[T gmath.Number](x float64, integer bool) T {
+	if integer {
+		x = math.Round(x)
+	}
+	return T(x)
+}
+
+// Convert returns a copy of v with the elements converted to the type To.
+// Floating-point values are rounded to the nearest integer when To is an
+// integer type; out of range values follow Go's conversion rules.
+//
+// Parameters:
+//   - v: The vector to convert.
+//
+// Returns:
+//   - A new vector of type VecOf[To].
+func Convert[To, From gmath.Number](v VecOf[From]) VecOf[To] {
+	round := isInteger[To]() && !isInteger[From]()
+	c := make(VecOf[To], len(v))
+	for i, e := range v {
+		if round {
+			c[i] = To(math.Round(float64(e)))
+		} else {
+			c[i] = To(e)
+		}
+	}
+	return c
+}
+
+// Equals reports whether v and b have the same size and exactly equal
+// elements. Following IEEE 754, NaN is not equal to itself and 0 equals -0;
+// use ApproxEquals for results of floating-point computations.
+func (v *VecOf[T]) Equals(b VecOf[T]) bool {
 	if len(*v) != len(b) {
 		return false
 	}
 
-	for i, v := range b {
-		if v != b[i] {
+	for i, e := range b {
+		if (*v)[i] != e {
 			return false
 		}
 	}
 	return true
 }
 
-func (v *Vec) Len() int {
+func (v *VecOf[T]) Len() int {
 	return len(*v)
 }
 
-func (v *Vec) Unit() Vec {
+func (v *VecOf[T]) Unit() VecOf[T] {
 	l := v.Norm()
 	size := v.Len()
 
+	c := make(VecOf[T], size)
 	if l == 0 {
-		return Zero(size)
+		return c
 	}
 
-	c := make([]float64, size)
-
+	integer := isInteger[T]()
 	for i, e := range *v {
-		c[i] = e / l
+		c[i] = fromFloat[T](float64(e)/l, integer)
 	}
 	return c
 }
 
-func (v *Vec) Norm() float64 {
+func (v *VecOf[T]) Norm() float64 {
 	acc := 0.0
 	for _, el := range *v {
-		acc += el * el
+		acc += float64(el) * float64(el)
 	}
 	return math.Sqrt(acc)
 }
 
-func (v *Vec) Add(value any) Vec {
-	if u, ok := value.(float64); ok {
-		c := make([]float64, v.Len())
-		for i, n := range *v {
-			c[i] = n + u
-		}
-		return c
-	}
-	if u, ok := value.(Vec); ok {
-		assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
-		c := make([]float64, v.Len())
-		for i := range v.Len() {
-			c[i] = u[i] + (*v)[i]
-		}
-		return c
+// Add adds a number or a vector to v. It accepts a number of any integer or
+// float type and a slice or VecOf of any such element type, converted to
+// the element type of v; integers convert exactly, without going through
+// float64. It panics for other types or a size mismatch; AddScalar, AddVec
+// and AddChecked are the type-safe equivalents.
+func (v *VecOf[T]) Add(value any) VecOf[T] {
+	u, n, isVec := operand[T](value)
+	if isVec {
+		return v.AddVec(u)
 	}
-	panic("The parameter 'value' must be float64 or an instance of Vec")
+	return v.AddScalar(n)
 }
 
-func (v *Vec) Sub(value any) Vec {
-	if u, ok := value.(float64); ok {
-		c := make([]float64, v.Len())
-		for i, n := range *v {
-			c[i] = n - u
-		}
-		return c
-	}
-	if u, ok := value.(Vec); ok {
-		assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
-		c := make([]float64, v.Len())
-		for i := range v.Len() {
-			c[i] = (*v)[i] - u[i]
-		}
-		return c
+// Sub subtracts a number or a vector from v. It accepts the same types as
+// Add; SubScalar, SubVec and SubChecked are the type-safe equivalents.
+func (v *VecOf[T]) Sub(value any) VecOf[T] {
+	u, n, isVec := operand[T](value)
+	if isVec {
+		return v.SubVec(u)
 	}
-	panic("The parameter 'value' must be float64 or an instance of Vec")
+	return v.SubScalar(n)
 }
 
-func (v *Vec) Scale(n float64) Vec {
-	c := make([]float64, v.Len())
+func (v *VecOf[T]) Scale(n T) VecOf[T] {
+	c := make(VecOf[T], v.Len())
 
 	for i := range *v {
 		c[i] = (*v)[i] * n
@@ -99,49 +134,54 @@ func (v *Vec) Scale(n float64) Vec {
 	return c
 }
 
-func (v *Vec) Dot(u Vec) float64 {
+func (v *VecOf[T]) Dot(u VecOf[T]) T {
 	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
-	acc := 0.0
+	var acc T
 	for i := range *v {
 		acc += (*v)[i] * u[i]
 	}
 	return acc
 }
 
-func (v *Vec) DistanceTo(u Vec) float64 {
-	t := v.Sub(u)
-	return t.Norm()
+func (v *VecOf[T]) DistanceTo(u VecOf[T]) float64 {
+	assert.AssertEq(len(u), v.Len(), "Vectors must have the same size")
+	acc := 0.0
+	for i, e := range *v {
+		d := float64(e) - float64(u[i])
+		acc += d * d
+	}
+	return math.Sqrt(acc)
 }
 
-func (v *Vec) Fill(value float64) {
+func (v *VecOf[T]) Fill(value T) {
 	for i := range v.Len() {
 		(*v)[i] = value
 	}
 }
 
-func (v *Vec) Inv() Vec {
+func (v *VecOf[T]) Inv() VecOf[T] {
 	l := v.Len()
-	c := make([]float64, l)
+	c := make(VecOf[T], l)
 	for i, e := range *v {
 		c[l-i-1] = e
 	}
 	return c
 }
 
-func (v *Vec) Resize(size int) Vec {
+func (v *VecOf[T]) Resize(size int) VecOf[T] {
 	if size < v.Len() {
 		return (*v)[:size]
 	}
-	c := make([]float64, size)
+	c := make(VecOf[T], size)
 	copy(c, *v)
 	return c
 }
 
-func (v *Vec) Concat(u Vec) Vec {
+func (v *VecOf[T]) Concat(u VecOf[T]) VecOf[T] {
 	return append((*v), u...)
 }
-func (v *Vec) Map(cb func(float64) float64) Vec {
-	c := make([]float64, v.Len())
+func (v *VecOf[T]) Map(cb func(T) T) VecOf[T] {
+	c := make(VecOf[T], v.Len())
 	for i, e := range *v {
 		c[i] = cb(e)
 	}
@@ -154,10 +194,15 @@ func (v *Vec) Map(cb func(float64) float64) Vec {
 
 const ERROR_2D_METHOD = "This method is only avalaible for 2d *vetors"
 
-func (v *Vec) ProjectOn(u Vec) Vec {
+func (v *VecOf[T]) ProjectOn(u VecOf[T]) VecOf[T] {
 	assert.AssertEq(v.Len(), 2, ERROR_2D_METHOD)
-	unit := u.Unit()
-	return unit.Scale(v.Dot(u) / u.Norm())
+	n := u.Norm()
+	if n == 0 {
+		return make(VecOf[T], 2)
+	}
+	f := (float64((*v)[0])*float64(u[0]) + float64((*v)[1])*float64(u[1])) / (n * n)
+	integer := isInteger[T]()
+	return VecOf[T]{fromFloat[T](float64(u[0])*f, integer), fromFloat[T](float64(u[1])*f, integer)}
 }
 
 // Rot rotates a 2D vector by a specified angle.
@@ -167,18 +212,13 @@ func (v *Vec) ProjectOn(u Vec) Vec {
 //
 // Returns:
 //   - The rotated 2D vector.
-func (v *Vec) Rot(angle float64) Vec {
+func (v *VecOf[T]) Rot(angle float64) VecOf[T] {
 	assert.AssertEq(v.Len(), 2, ERROR_2D_METHOD)
-	x := (*v)[0]*math.Cos(angle) - (*v)[1]*math.Sin(angle)
-	y := (*v)[0]*math.Sin(angle) + (*v)[1]*math.Cos(angle)
-	return Vec{x, y}
-}
-
-func (v *Vec) IsParallelTo(u Vec) bool {
-	assert.AssertEq(v.Len(), 2, ERROR_2D_METHOD)
-	assert.AssertEq(u.Len(), 2, ERROR_2D_METHOD)
-
-	return math.Abs((*v)[0]*u[1]-(*v)[1]*u[0]) < 1e-9
+	vx, vy := float64((*v)[0]), float64((*v)[1])
+	x := vx*math.Cos(angle) - vy*math.Sin(angle)
+	y := vx*math.Sin(angle) + vy*math.Cos(angle)
+	integer := isInteger[T]()
+	return VecOf[T]{fromFloat[T](x, integer), fromFloat[T](y, integer)}
 }
 
 // Vec2FromAngle creates a 2D vector from an angle.
@@ -198,6 +238,8 @@ func Vec2FromAngle(angle float64) Vec {
 
 const ERROR_3D_METHOD = "This method is only available for 3d vectors"
 
+const ERROR_4D_METHOD = "This method is only available for 4d vectors"
+
 // Prod3d calculates the cross product of two 3D vectors.
 //
 // Parameters:
@@ -205,17 +247,48 @@ const ERROR_3D_METHOD = "This method is only available for 3d vectors"
 //
 // Returns:
 //   - The cross product of the two vectors as a new 3D vector.
-func (v *Vec) Prod3d(u Vec) Vec {
+func (v *VecOf[T]) Prod3d(u VecOf[T]) VecOf[T] {
 	assert.AssertEq(v.Len(), 3, ERROR_3D_METHOD)
 	assert.AssertEq(u.Len(), 3, ERROR_3D_METHOD)
 
-	return Vec{
+	return VecOf[T]{
 		(*v)[1]*u[2] - (*v)[2]*u[1],
 		(*v)[2]*u[0] - (*v)[0]*u[2],
 		(*v)[0]*u[1] - (*v)[1]*u[0],
 	}
 }
 
+// RotAroundAxis rotates a 3D vector around an axis using Rodrigues' rotation
+// formula, following the right-hand rule.
+//
+// Parameters:
+//   - axis: The 3D rotation axis; it does not need to be normalized.
+//   - angle: The angle in radians to rotate the vector.
+//
+// Returns:
+//   - The rotated 3D vector, or a copy of v if axis is the zero vector.
+func (v *VecOf[T]) RotAroundAxis(axis VecOf[T], angle float64) VecOf[T] {
+	assert.AssertEq(v.Len(), 3, ERROR_3D_METHOD)
+	assert.AssertEq(axis.Len(), 3, ERROR_3D_METHOD)
+
+	n := axis.Norm()
+	if n == 0 {
+		return append(VecOf[T](nil), *v...)
+	}
+	p := Vec{float64((*v)[0]), float64((*v)[1]), float64((*v)[2])}
+	k := Vec{float64(axis[0]) / n, float64(axis[1]) / n, float64(axis[2]) / n}
+	s, c := math.Sincos(angle)
+	kxv := k.Prod3d(p)
+	kv := k.Dot(p) * (1 - c)
+
+	r := make(VecOf[T], 3)
+	integer := isInteger[T]()
+	for i := range r {
+		r[i] = fromFloat[T](p[i]*c+kxv[i]*s+k[i]*kv, integer)
+	}
+	return r
+}
+
 func Zero(size int) Vec {
 	return make([]float64, size)
 }
@@ -232,10 +305,9 @@ func Filled(size int, value float64) Vec {
 	return c
 }
 
+// Rand returns a vector of size values drawn uniformly from [0, 1) with the
+// global source of math/rand/v2. Use RandUniform with a seeded source for
+// reproducible values.
 func Rand(size int) Vec {
-	c := make([]float64, size)
-	for i := range size {
-		c[i] = rand.Float64()
-	}
-	return c
+	return RandUniform(globalRand, size, 0, 1)
 }